@@ -0,0 +1,554 @@
+package hyphenate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"unsafe"
+
+	"github.com/npillmayer/hyphenate/dat"
+)
+
+// Compiled dictionaries
+//
+// A compiled dictionary is a frozen dictionary in a binary format, which loads
+// without re-parsing pattern files and without rebuilding the trie.
+//
+// The format is little-endian throughout. A file starts with a fixed header:
+//
+//	offset  size  field
+//	     0     8  magic "HYPHDICT"
+//	     8     2  format version (currently 3)
+//	    10     2  byte order mark 0xFEFF
+//	    12     4  number of sections
+//	    16     8  length of the body, i.e. the file without the header
+//	    24     4  CRC-32 (Castagnoli) of the body
+//	    28     4  reserved, 0
+//
+// The body starts with a section table of 24-byte entries
+// (tag [4]byte, reserved uint32, offset uint64, length uint64), followed by
+// the sections. Offsets are relative to the start of the file. Every section
+// starts at an offset which is a multiple of 8, so that arrays may be used
+// in place from a memory-mapped file.
+//
+// Section META holds scalar data as JSON (identifier, options, trie root,
+// alphabet size, hyphenmins, replacements of non-standard patterns and
+// exceptions, declared letters and the metadata of the pattern source), with
+// a schema of its own (see binaryMeta). Section EXCP holds the exceptions as
+// uvarint-encoded records (see appendExceptions). Optional section NEXT holds
+// the second level of a two-level dictionary (see CompoundReader) as a
+// complete compiled dictionary. Optional sections LINE and SRCN hold the
+// source lines of the patterns by trie position and the name of the pattern
+// source, for Dictionary.Explain (see LineReader and CompileOptions). All other sections hold
+// arrays of fixed-size integers.
+
+const (
+	binaryMagic     = "HYPHDICT"
+	binaryVersion   = 3
+	binaryBOM       = 0xFEFF
+	binaryHeaderLen = 32
+	binarySectLen   = 24
+)
+
+// Errors returned by ReadDictionary.
+var (
+	ErrNotADictionary     = errors.New("not a compiled hyphenation dictionary")
+	ErrUnsupportedVersion = errors.New("unsupported compiled dictionary version")
+	ErrChecksum           = errors.New("compiled dictionary checksum mismatch")
+	ErrCorrupt            = errors.New("corrupt compiled dictionary")
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+type binarySection struct {
+	tag  string
+	data []byte
+}
+
+// CompileOptions control the compiled binary form of a dictionary, see
+// WriteCompiled.
+type CompileOptions struct {
+	// SourceLines keeps the source lines of the patterns and the name of the
+	// pattern source, which Explain reports. They add about a sixth to the
+	// size of a compiled dictionary.
+	SourceLines bool
+}
+
+// WriteTo writes the dictionary in compiled binary form to w, without source
+// lines. It implements io.WriterTo. Use ReadDictionary to load the dictionary
+// again.
+func (dict *Dictionary) WriteTo(w io.Writer) (int64, error) {
+	return dict.WriteCompiled(w, CompileOptions{})
+}
+
+// WriteCompiled is like WriteTo, with options opts.
+func (dict *Dictionary) WriteCompiled(w io.Writer, opts CompileOptions) (int64, error) {
+	sections, err := dict.binarySections(opts)
+	if err != nil {
+		return 0, err
+	}
+	body := make([]byte, len(sections)*binarySectLen)
+	offset := uint64(binaryHeaderLen + len(body))
+	for i, s := range sections {
+		for offset%8 != 0 {
+			body = append(body, 0)
+			offset++
+		}
+		entry := body[i*binarySectLen:]
+		copy(entry[0:4], s.tag)
+		binary.LittleEndian.PutUint64(entry[8:], offset)
+		binary.LittleEndian.PutUint64(entry[16:], uint64(len(s.data)))
+		body = append(body, s.data...)
+		offset += uint64(len(s.data))
+	}
+	header := make([]byte, binaryHeaderLen)
+	copy(header, binaryMagic)
+	binary.LittleEndian.PutUint16(header[8:], binaryVersion)
+	binary.LittleEndian.PutUint16(header[10:], binaryBOM)
+	binary.LittleEndian.PutUint32(header[12:], uint32(len(sections)))
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(body)))
+	binary.LittleEndian.PutUint32(header[24:], crc32.Checksum(body, castagnoli))
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(body)
+	return int64(n + m), err
+}
+
+func (dict *Dictionary) binarySections(opts CompileOptions) ([]binarySection, error) {
+	db, ok := dict.patterns.(*datBackend)
+	if !ok || !db.frozen || dict.patternsV == nil {
+		return nil, fmt.Errorf("dictionary %q has no frozen patterns", dict.Identifier)
+	}
+	d, store := db.compiled, dict.patternsV
+	var noHyphen []string
+	for _, seq := range dict.noHyphen {
+		noHyphen = append(noHyphen, string(seq))
+	}
+	var exceptReps map[string]map[int]binaryReplacement
+	for word, reps := range dict.exceptReps {
+		if exceptReps == nil {
+			exceptReps = make(map[string]map[int]binaryReplacement)
+		}
+		exceptReps[word] = newBinaryReplacements(reps)
+	}
+	meta, err := json.Marshal(binaryMeta{
+		Identifier:   dict.Identifier,
+		Options:      newBinaryOptions(dict.Options),
+		Root:         d.Root,
+		Sigma:        d.Sigma,
+		MinLeft:      d.MinLeft,
+		MinRight:     d.MinRight,
+		StoreWidth:   store.width,
+		Replacements: newBinaryReplacements(store.replacements),
+		Metadata:     newBinaryMetadata(dict.Metadata),
+		Letters:      dict.letters,
+		NoHyphen:     noHyphen,
+		ExceptReps:   exceptReps,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sections := []binarySection{
+		{"META", meta},
+		{"BASE", appendInt32s(nil, d.Base)},
+		{"CHCK", appendInt32s(nil, d.Check)},
+		{"FAIL", appendInt32s(nil, d.Fail)},
+		{"OUTP", appendInt32s(nil, d.Output)},
+		{"DPTH", d.Depth},
+		{"MTOP", appendUint16s(nil, d.MapPaged.Top[:])},
+		{"MPGS", appendUint16s(nil, d.MapPaged.Pages)},
+		{"SRUN", appendInt32s(nil, d.MapSupp.Runes)},
+		{"SIDS", appendUint16s(nil, d.MapSupp.IDs)},
+		{"SLEN", store.length},
+		{"SPAY", store.payload},
+		{"EXCP", appendExceptions(nil, dict.exceptions)},
+	}
+	if opts.SourceLines && len(store.lines) > 0 {
+		sections = append(sections, binarySection{"LINE", appendInt32s(nil, store.lines)})
+	}
+	if opts.SourceLines && dict.source != "" {
+		sections = append(sections, binarySection{"SRCN", []byte(dict.source)})
+	}
+	if dict.nextLevel != nil {
+		var next bytes.Buffer
+		if _, err := dict.nextLevel.WriteCompiled(&next, opts); err != nil {
+			return nil, err
+		}
+		sections = append(sections, binarySection{"NEXT", next.Bytes()})
+	}
+	return sections, nil
+}
+
+// ReadDictionary reads a dictionary in compiled binary form, as written by
+// Dictionary.WriteTo.
+//
+// Files which are not compiled dictionaries, have been written by an
+// incompatible version or fail the consistency checks are reported with
+// errors wrapping ErrNotADictionary, ErrUnsupportedVersion, ErrChecksum or
+// ErrCorrupt, respectively.
+func ReadDictionary(r io.Reader) (*Dictionary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDictionary(data)
+}
+
+// CompiledMetadata returns the metadata of the pattern source of a
+// dictionary in compiled binary form (see ReadDictionary), without decoding
+// its patterns. It returns nil if the metadata is unknown.
+func CompiledMetadata(data []byte) (*Metadata, error) {
+	sections, err := binarySectionTable(data)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := decodeMeta(sections["META"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: section META: %v", ErrCorrupt, err)
+	}
+	return meta.Metadata.metadata(), nil
+}
+
+// decodeDictionary decodes a compiled dictionary. On little-endian hosts the
+// arrays of the trie and the pattern store alias data, which therefore must
+// neither be modified nor released while the dictionary is in use.
+func decodeDictionary(data []byte) (*Dictionary, error) {
+	sections, err := binarySectionTable(data)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := decodeMeta(sections["META"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: section META: %v", ErrCorrupt, err)
+	}
+	d := &dat.DAT{
+		Root:     meta.Root,
+		Sigma:    meta.Sigma,
+		MinLeft:  meta.MinLeft,
+		MinRight: meta.MinRight,
+	}
+	var arrays []error
+	d.Base, err = int32s(sections["BASE"])
+	arrays = append(arrays, err)
+	d.Check, err = int32s(sections["CHCK"])
+	arrays = append(arrays, err)
+	d.Fail, err = int32s(sections["FAIL"])
+	arrays = append(arrays, err)
+	d.Output, err = int32s(sections["OUTP"])
+	arrays = append(arrays, err)
+	d.Depth = sections["DPTH"]
+	top, err := uint16s(sections["MTOP"])
+	arrays = append(arrays, err)
+	d.MapPaged.Pages, err = uint16s(sections["MPGS"])
+	arrays = append(arrays, err)
+	d.MapSupp.Runes, err = int32s(sections["SRUN"])
+	arrays = append(arrays, err)
+	d.MapSupp.IDs, err = uint16s(sections["SIDS"])
+	arrays = append(arrays, err)
+	lines, err := int32s(sections["LINE"])
+	arrays = append(arrays, err)
+	if err := errors.Join(arrays...); err != nil {
+		return nil, err
+	}
+	if len(top) != len(d.MapPaged.Top) {
+		return nil, fmt.Errorf("%w: alphabet map has %d pages", ErrCorrupt, len(top))
+	}
+	copy(d.MapPaged.Top[:], top)
+	store := &patternStore{
+		width:        meta.StoreWidth,
+		length:       sections["SLEN"],
+		payload:      sections["SPAY"],
+		replacements: replacements(meta.Replacements),
+		lines:        lines,
+	}
+	if err := validateCompiled(d, store); err != nil {
+		return nil, err
+	}
+	exceptions, err := decodeExceptions(sections["EXCP"])
+	if err != nil {
+		return nil, err
+	}
+	var next *Dictionary
+	if data, ok := sections["NEXT"]; ok {
+		if next, err = decodeDictionary(data); err != nil {
+			return nil, fmt.Errorf("section NEXT: %w", err)
+		}
+	}
+	var exceptReps map[string]map[int]*Replacement
+	for word, reps := range meta.ExceptReps {
+		if exceptReps == nil {
+			exceptReps = make(map[string]map[int]*Replacement)
+		}
+		exceptReps[word] = replacements(reps)
+	}
+	var noHyphen [][]rune
+	for _, seq := range meta.NoHyphen {
+		noHyphen = append(noHyphen, []rune(seq))
+	}
+	return &Dictionary{
+		exceptions: exceptions,
+		patterns:   &datBackend{frozen: true, compiled: d},
+		patternsV:  store,
+		Identifier: meta.Identifier,
+		Options:    meta.Options.options(),
+		Metadata:   meta.Metadata.metadata(),
+		letters:    meta.Letters,
+		exceptReps: exceptReps,
+		noHyphen:   noHyphen,
+		nextLevel:  next,
+		source:     string(sections["SRCN"]),
+	}, nil
+}
+
+// binarySectionTable checks the header and checksum of a compiled dictionary
+// and returns its sections by tag.
+func binarySectionTable(data []byte) (map[string][]byte, error) {
+	if len(data) < binaryHeaderLen || string(data[:8]) != binaryMagic {
+		return nil, ErrNotADictionary
+	}
+	if bom := binary.LittleEndian.Uint16(data[10:]); bom != binaryBOM {
+		return nil, fmt.Errorf("%w: unknown byte order mark %#04x", ErrNotADictionary, bom)
+	}
+	if v := binary.LittleEndian.Uint16(data[8:]); v != binaryVersion {
+		return nil, fmt.Errorf("%w: file has version %d, expected %d", ErrUnsupportedVersion, v, binaryVersion)
+	}
+	count := binary.LittleEndian.Uint32(data[12:])
+	bodyLen := binary.LittleEndian.Uint64(data[16:])
+	body := data[binaryHeaderLen:]
+	if uint64(len(body)) != bodyLen {
+		return nil, fmt.Errorf("%w: body has %d bytes, expected %d", ErrCorrupt, len(body), bodyLen)
+	}
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(data[24:]) {
+		return nil, ErrChecksum
+	}
+	if uint64(count)*binarySectLen > bodyLen {
+		return nil, fmt.Errorf("%w: section table exceeds file", ErrCorrupt)
+	}
+	sections := make(map[string][]byte, count)
+	for i := 0; i < int(count); i++ {
+		entry := body[i*binarySectLen:]
+		tag := string(entry[0:4])
+		offset := binary.LittleEndian.Uint64(entry[8:])
+		length := binary.LittleEndian.Uint64(entry[16:])
+		if offset%8 != 0 || offset > uint64(len(data)) || length > uint64(len(data))-offset {
+			return nil, fmt.Errorf("%w: section %s out of bounds", ErrCorrupt, tag)
+		}
+		sections[tag] = data[offset : offset+length : offset+length]
+	}
+	for _, tag := range []string{"META", "BASE", "CHCK", "MTOP", "SLEN", "SPAY", "EXCP"} {
+		if _, ok := sections[tag]; !ok {
+			return nil, fmt.Errorf("%w: missing section %s", ErrCorrupt, tag)
+		}
+	}
+	return sections, nil
+}
+
+// validateCompiled checks the invariants the lookup functions rely on, so a
+// corrupted file cannot cause out-of-range accesses.
+func validateCompiled(d *dat.DAT, store *patternStore) error {
+	n := len(d.Base)
+	if n == 0 || len(d.Check) != n || int(d.Root) >= n {
+		return fmt.Errorf("%w: inconsistent trie arrays", ErrCorrupt)
+	}
+	if len(d.Fail)+len(d.Output)+len(d.Depth) != 0 && !d.HasFailureLinks() {
+		return fmt.Errorf("%w: inconsistent failure links", ErrCorrupt)
+	}
+	for i := range d.Fail {
+		if d.Fail[i] < 0 || int(d.Fail[i]) >= n || d.Output[i] < 0 || int(d.Output[i]) >= n {
+			return fmt.Errorf("%w: failure link out of range", ErrCorrupt)
+		}
+	}
+	if d.HasFailureLinks() {
+		if err := validateFailureLinks(d); err != nil {
+			return err
+		}
+	}
+	if d.Sigma == 0 {
+		return fmt.Errorf("%w: empty alphabet", ErrCorrupt)
+	}
+	pages := d.MapPaged.NumPages()
+	if len(d.MapPaged.Pages)%256 != 0 {
+		return fmt.Errorf("%w: alphabet map has a partial page", ErrCorrupt)
+	}
+	for _, id := range d.MapPaged.Pages {
+		if id > d.Sigma {
+			return fmt.Errorf("%w: alphabet ID out of range", ErrCorrupt)
+		}
+	}
+	for _, pi := range d.MapPaged.Top {
+		if int(pi) > pages {
+			return fmt.Errorf("%w: alphabet map page out of range", ErrCorrupt)
+		}
+	}
+	if len(d.MapSupp.Runes) != len(d.MapSupp.IDs) {
+		return fmt.Errorf("%w: inconsistent supplementary alphabet map", ErrCorrupt)
+	}
+	for i, id := range d.MapSupp.IDs {
+		if id > d.Sigma || i > 0 && d.MapSupp.Runes[i] <= d.MapSupp.Runes[i-1] {
+			return fmt.Errorf("%w: inconsistent supplementary alphabet map", ErrCorrupt)
+		}
+	}
+	if store.width > 16 || len(store.payload) != len(store.length)*int(store.width) {
+		return fmt.Errorf("%w: inconsistent pattern store", ErrCorrupt)
+	}
+	for _, l := range store.length {
+		if l != absentPayload && l > store.width {
+			return fmt.Errorf("%w: pattern payload too large", ErrCorrupt)
+		}
+	}
+	return nil
+}
+
+// validateFailureLinks checks that the depth of every state of the trie is
+// the length of its path, and that failure and output links lead to
+// shallower states. Neither the failure walk of dat.DAT.Next nor the output
+// chain of matchPatterns can therefore loop. States are in use if they are
+// the root or have a parent, i.e. Check[s] != 0.
+func validateFailureLinks(d *dat.DAT) error {
+	n := int32(len(d.Base))
+	root := int32(d.Root)
+	used := func(s int32) bool {
+		return s == root || s > 0 && s < n && d.Check[s] != 0
+	}
+	if d.Depth[root] != 0 || d.Output[root] != 0 {
+		return fmt.Errorf("%w: inconsistent root state", ErrCorrupt)
+	}
+	for s := int32(1); s < n; s++ {
+		if s == root || d.Check[s] == 0 {
+			continue
+		}
+		if parent := d.Check[s]; !used(parent) || d.Depth[s] != d.Depth[parent]+1 {
+			return fmt.Errorf("%w: inconsistent depth of state %d", ErrCorrupt, s)
+		}
+		if fail := d.Fail[s]; !used(fail) || d.Depth[fail] >= d.Depth[s] {
+			return fmt.Errorf("%w: failure link of state %d does not lead to a shallower state", ErrCorrupt, s)
+		}
+		if out := d.Output[s]; out != 0 && (!used(out) || d.Depth[out] >= d.Depth[s]) {
+			return fmt.Errorf("%w: output link of state %d does not lead to a shallower state", ErrCorrupt, s)
+		}
+	}
+	return nil
+}
+
+// appendExceptions encodes exceptions, sorted by word, as a uvarint count
+// followed by records (uvarint len(word), word, uvarint len(positions),
+// uvarint positions...).
+func appendExceptions(dst []byte, exceptions map[string][]int) []byte {
+	words := make([]string, 0, len(exceptions))
+	for word := range exceptions {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	dst = binary.AppendUvarint(dst, uint64(len(words)))
+	for _, word := range words {
+		dst = binary.AppendUvarint(dst, uint64(len(word)))
+		dst = append(dst, word...)
+		positions := exceptions[word]
+		dst = binary.AppendUvarint(dst, uint64(len(positions)))
+		for _, p := range positions {
+			dst = binary.AppendUvarint(dst, uint64(max(p, 0)))
+		}
+	}
+	return dst
+}
+
+func decodeExceptions(data []byte) (map[string][]int, error) {
+	corrupt := fmt.Errorf("%w: section EXCP", ErrCorrupt)
+	// uvarint decodes the next value, which must not exceed limit
+	uvarint := func(limit int) (int, bool) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 || v > uint64(limit) {
+			return 0, false
+		}
+		data = data[n:]
+		return int(v), true
+	}
+	count, ok := uvarint(len(data))
+	if !ok {
+		return nil, corrupt
+	}
+	exceptions := make(map[string][]int, count)
+	for i := 0; i < count; i++ {
+		l, ok := uvarint(len(data))
+		if !ok {
+			return nil, corrupt
+		}
+		word := string(data[:l])
+		data = data[l:]
+		if l, ok = uvarint(len(data)); !ok {
+			return nil, corrupt
+		}
+		positions := make([]int, l)
+		for j := range positions {
+			if positions[j], ok = uvarint(255); !ok {
+				return nil, corrupt
+			}
+		}
+		exceptions[word] = positions
+	}
+	return exceptions, nil
+}
+
+func appendInt32s(dst []byte, values []int32) []byte {
+	for _, v := range values {
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(v))
+	}
+	return dst
+}
+
+func appendUint16s(dst []byte, values []uint16) []byte {
+	for _, v := range values {
+		dst = binary.LittleEndian.AppendUint16(dst, v)
+	}
+	return dst
+}
+
+// int32s returns the little-endian array data as a slice, aliasing data if
+// possible.
+func int32s(data []byte) ([]int32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("%w: misaligned array", ErrCorrupt)
+	}
+	if canAlias(data, 4) {
+		return unsafe.Slice((*int32)(unsafe.Pointer(unsafe.SliceData(data))), len(data)/4), nil
+	}
+	values := make([]int32, len(data)/4)
+	for i := range values {
+		values[i] = int32(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return values, nil
+}
+
+// uint16s returns the little-endian array data as a slice, aliasing data if
+// possible.
+func uint16s(data []byte) ([]uint16, error) {
+	if len(data)%2 != 0 {
+		return nil, fmt.Errorf("%w: misaligned array", ErrCorrupt)
+	}
+	if canAlias(data, 2) {
+		return unsafe.Slice((*uint16)(unsafe.Pointer(unsafe.SliceData(data))), len(data)/2), nil
+	}
+	values := make([]uint16, len(data)/2)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return values, nil
+}
+
+// nativeLittleEndian is true if the host stores integers little-endian, i.e.,
+// in the byte order of compiled dictionaries.
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// canAlias reports whether data may be used in place as an array of integers
+// of the given size.
+func canAlias(data []byte, size uintptr) bool {
+	return nativeLittleEndian && len(data) > 0 &&
+		uintptr(unsafe.Pointer(unsafe.SliceData(data)))%size == 0
+}
@@ -0,0 +1,190 @@
+package hyphenate
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Section META of a compiled dictionary
+//
+// The JSON schema of section META is part of the binary format: its field
+// names are fixed by the tags below, not by the Go types of the package, so
+// renaming a field of Options or Metadata does not change the format. Any
+// change of the schema requires a new binaryVersion. Readers reject unknown
+// fields.
+
+// binaryMeta is the content of section META.
+type binaryMeta struct {
+	Identifier   string                               `json:"identifier"`
+	Options      binaryOptions                        `json:"options"`
+	Root         uint32                               `json:"root"`
+	Sigma        uint16                               `json:"sigma"`
+	MinLeft      uint8                                `json:"min_left"`
+	MinRight     uint8                                `json:"min_right"`
+	StoreWidth   uint8                                `json:"store_width"`
+	Replacements map[int]binaryReplacement            `json:"replacements,omitempty"`
+	Metadata     *binaryMetadata                      `json:"metadata,omitempty"`
+	Letters      map[rune]rune                        `json:"letters,omitempty"`
+	NoHyphen     []string                             `json:"no_hyphen,omitempty"`
+	ExceptReps   map[string]map[int]binaryReplacement `json:"exception_replacements,omitempty"`
+}
+
+// binaryOptions is the schema of Options.
+type binaryOptions struct {
+	LeftMin          int   `json:"left_min"`
+	RightMin         int   `json:"right_min"`
+	SkipAllCaps      bool  `json:"skip_all_caps"`
+	Matcher          uint8 `json:"matcher"`
+	CompoundLeftMin  int   `json:"compound_left_min"`
+	CompoundRightMin int   `json:"compound_right_min"`
+	HyphenChar       rune  `json:"hyphen_char"`
+	HyphenPenalty    int   `json:"hyphen_penalty"`
+	ExHyphenPenalty  int   `json:"ex_hyphen_penalty"`
+	RepeatHardHyphen bool  `json:"repeat_hard_hyphen"`
+}
+
+// binaryReplacement is the schema of Replacement.
+type binaryReplacement struct {
+	Pre   string `json:"pre"`
+	Post  string `json:"post"`
+	Start int    `json:"start"`
+	Cut   int    `json:"cut"`
+}
+
+// binaryMetadata is the schema of Metadata.
+type binaryMetadata struct {
+	Title           string          `json:"title,omitempty"`
+	Language        string          `json:"language,omitempty"`
+	Tag             string          `json:"tag,omitempty"`
+	Version         string          `json:"version,omitempty"`
+	Notice          string          `json:"notice,omitempty"`
+	Copyright       string          `json:"copyright,omitempty"`
+	Source          string          `json:"source,omitempty"`
+	Authors         []binaryAuthor  `json:"authors,omitempty"`
+	Licenses        []binaryLicense `json:"licenses,omitempty"`
+	GenerationMins  [2]int          `json:"generation_mins"`  // left, right
+	TypesettingMins [2]int          `json:"typesetting_mins"` // left, right
+}
+
+type binaryAuthor struct {
+	Name    string `json:"name"`
+	Contact string `json:"contact,omitempty"`
+}
+
+type binaryLicense struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// decodeMeta decodes section META, rejecting fields which are not part of
+// the schema.
+func decodeMeta(data []byte) (*binaryMeta, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	meta := &binaryMeta{}
+	if err := dec.Decode(meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func newBinaryOptions(o Options) binaryOptions {
+	return binaryOptions{
+		LeftMin:          o.LeftMin,
+		RightMin:         o.RightMin,
+		SkipAllCaps:      o.SkipAllCaps,
+		Matcher:          uint8(o.Matcher),
+		CompoundLeftMin:  o.CompoundLeftMin,
+		CompoundRightMin: o.CompoundRightMin,
+		HyphenChar:       o.HyphenChar,
+		HyphenPenalty:    o.HyphenPenalty,
+		ExHyphenPenalty:  o.ExHyphenPenalty,
+		RepeatHardHyphen: o.RepeatHardHyphen,
+	}
+}
+
+func (o binaryOptions) options() Options {
+	return Options{
+		LeftMin:          o.LeftMin,
+		RightMin:         o.RightMin,
+		SkipAllCaps:      o.SkipAllCaps,
+		Matcher:          Matcher(o.Matcher),
+		CompoundLeftMin:  o.CompoundLeftMin,
+		CompoundRightMin: o.CompoundRightMin,
+		HyphenChar:       o.HyphenChar,
+		HyphenPenalty:    o.HyphenPenalty,
+		ExHyphenPenalty:  o.ExHyphenPenalty,
+		RepeatHardHyphen: o.RepeatHardHyphen,
+	}
+}
+
+func newBinaryReplacements(reps map[int]*Replacement) map[int]binaryReplacement {
+	if len(reps) == 0 {
+		return nil
+	}
+	m := make(map[int]binaryReplacement, len(reps))
+	for k, rep := range reps {
+		m[k] = binaryReplacement{Pre: rep.Pre, Post: rep.Post, Start: rep.Start, Cut: rep.Cut}
+	}
+	return m
+}
+
+func replacements(reps map[int]binaryReplacement) map[int]*Replacement {
+	if reps == nil {
+		return nil
+	}
+	m := make(map[int]*Replacement, len(reps))
+	for k, rep := range reps {
+		m[k] = &Replacement{Pre: rep.Pre, Post: rep.Post, Start: rep.Start, Cut: rep.Cut}
+	}
+	return m
+}
+
+func newBinaryMetadata(md *Metadata) *binaryMetadata {
+	if md == nil {
+		return nil
+	}
+	b := &binaryMetadata{
+		Title:           md.Title,
+		Language:        md.Language,
+		Tag:             md.Tag,
+		Version:         md.Version,
+		Notice:          md.Notice,
+		Copyright:       md.Copyright,
+		Source:          md.Source,
+		GenerationMins:  [2]int{md.GenerationMins.Left, md.GenerationMins.Right},
+		TypesettingMins: [2]int{md.TypesettingMins.Left, md.TypesettingMins.Right},
+	}
+	for _, a := range md.Authors {
+		b.Authors = append(b.Authors, binaryAuthor{Name: a.Name, Contact: a.Contact})
+	}
+	for _, l := range md.Licenses {
+		b.Licenses = append(b.Licenses, binaryLicense{Name: l.Name, URL: l.URL, Text: l.Text})
+	}
+	return b
+}
+
+func (b *binaryMetadata) metadata() *Metadata {
+	if b == nil {
+		return nil
+	}
+	md := &Metadata{
+		Title:           b.Title,
+		Language:        b.Language,
+		Tag:             b.Tag,
+		Version:         b.Version,
+		Notice:          b.Notice,
+		Copyright:       b.Copyright,
+		Source:          b.Source,
+		GenerationMins:  Hyphenmins{Left: b.GenerationMins[0], Right: b.GenerationMins[1]},
+		TypesettingMins: Hyphenmins{Left: b.TypesettingMins[0], Right: b.TypesettingMins[1]},
+	}
+	for _, a := range b.Authors {
+		md.Authors = append(md.Authors, Author{Name: a.Name, Contact: a.Contact})
+	}
+	for _, l := range b.Licenses {
+		md.Licenses = append(md.Licenses, License{Name: l.Name, URL: l.URL, Text: l.Text})
+	}
+	return md
+}
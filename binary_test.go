@@ -0,0 +1,230 @@
+package hyphenate_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+func compile(t *testing.T, dict *hyphenate.Dictionary) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := dict.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo reports %d bytes, wrote %d", n, buf.Len())
+	}
+	return buf.Bytes()
+}
+
+// section returns section tag of a compiled dictionary.
+func section(data []byte, tag string) []byte {
+	count := int(binary.LittleEndian.Uint32(data[12:]))
+	for i := 0; i < count; i++ {
+		entry := data[32+i*24:]
+		if string(entry[:4]) == tag {
+			offset := binary.LittleEndian.Uint64(entry[8:])
+			return data[offset : offset+binary.LittleEndian.Uint64(entry[16:])]
+		}
+	}
+	return nil
+}
+
+func TestCompiledDictionaryRoundTrip(t *testing.T) {
+	dict := loadGerman(t)
+	data := compile(t, dict)
+	loaded, err := hyphenate.ReadDictionary(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Identifier != dict.Identifier || loaded.Options != dict.Options {
+		t.Fatalf("metadata differs: %q %+v, want %q %+v",
+			loaded.Identifier, loaded.Options, dict.Identifier, dict.Options)
+	}
+	if dict.Metadata == nil || !reflect.DeepEqual(loaded.Metadata, dict.Metadata) {
+		t.Fatalf("source metadata differs: %+v, want %+v", loaded.Metadata, dict.Metadata)
+	}
+	for _, word := range append(benchWords, "Zuckerbäcker", "Häuser") {
+		if got, want := loaded.Breaks(word), dict.Breaks(word); !reflect.DeepEqual(got, want) {
+			t.Errorf("breaks of %q: got %v, want %v", word, got, want)
+		}
+	}
+	if !bytes.Equal(compile(t, loaded), data) {
+		t.Errorf("re-compiling a loaded dictionary yields different output")
+	}
+}
+
+func TestCompiledDictionaryExtras(t *testing.T) {
+	source := "\\lccode`\\'=`\\'\n" + `\patterns{
+s1sz/sz=sz,1,3
+1ba 𐌰1𐌱
+}
+\hyphenation{
+ta-ble
+}`
+	dict, err := tex.LoadDictionary("extras", strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := hyphenate.ReadDictionary(bytes.NewReader(compile(t, dict)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := loaded.Options
+	opts.LeftMin, opts.RightMin = 1, 1
+	for _, word := range []string{"assza", "abababa", "𐌰𐌰𐌰𐌱𐌰𐌰", "table", "Table"} {
+		got, want := loaded.BreaksWith(word, opts), dict.BreaksWith(word, opts)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("breaks of %q: got %v, want %v", word, got, want)
+		}
+	}
+	if h := loaded.HyphenationString("table"); h != "ta-ble" {
+		t.Errorf("exception table: got %q", h)
+	}
+	if !loaded.IsLetter('\'') {
+		t.Errorf("declared letter ' is missing")
+	}
+}
+
+func TestCompiledDictionaryErrors(t *testing.T) {
+	data := compile(t, loadGerman(t))
+	modified := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), data...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, hyphenate.ErrNotADictionary},
+		{"pattern file", []byte(`\patterns{ a1b }`), hyphenate.ErrNotADictionary},
+		{"version", modified(func(b []byte) []byte {
+			binary.LittleEndian.PutUint16(b[8:], 99)
+			return b
+		}), hyphenate.ErrUnsupportedVersion},
+		{"byte order", modified(func(b []byte) []byte {
+			b[10], b[11] = b[11], b[10]
+			return b
+		}), hyphenate.ErrNotADictionary},
+		{"flipped bit", modified(func(b []byte) []byte {
+			b[len(b)/2] ^= 0x10
+			return b
+		}), hyphenate.ErrChecksum},
+		{"truncated", modified(func(b []byte) []byte {
+			return b[:len(b)-100]
+		}), hyphenate.ErrCorrupt},
+		{"failure self-loop", modified(func(b []byte) []byte {
+			check, fail := section(b, "CHCK"), section(b, "FAIL")
+			for s := 2; s < len(check)/4; s++ { // state 1 is the root
+				if binary.LittleEndian.Uint32(check[4*s:]) != 0 {
+					binary.LittleEndian.PutUint32(fail[4*s:], uint32(s))
+					break
+				}
+			}
+			binary.LittleEndian.PutUint32(b[24:], crc32.Checksum(b[32:], crc32.MakeTable(crc32.Castagnoli)))
+			return b
+		}), hyphenate.ErrCorrupt},
+		{"alphabet ID", modified(func(b []byte) []byte {
+			pages := section(b, "MPGS")
+			binary.LittleEndian.PutUint16(pages[2*'e':], 0xFFFF)
+			binary.LittleEndian.PutUint32(b[24:], crc32.Checksum(b[32:], crc32.MakeTable(crc32.Castagnoli)))
+			return b
+		}), hyphenate.ErrCorrupt},
+	}
+	for _, tt := range tests {
+		_, err := hyphenate.ReadDictionary(bytes.NewReader(tt.data))
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCompiledDictionarySchema(t *testing.T) {
+	meta := string(section(compile(t, loadGerman(t)), "META"))
+	for _, field := range []string{`"identifier":`, `"options":{"left_min":2,`, `"sigma":`} {
+		if !strings.Contains(meta, field) {
+			t.Errorf("section META should contain %s, is %s", field, meta)
+		}
+	}
+	if strings.Contains(meta, `"LeftMin"`) {
+		t.Errorf("section META should not contain Go field names, is %s", meta)
+	}
+}
+
+func TestOpenDictionary(t *testing.T) {
+	dict := loadGerman(t)
+	path := filepath.Join(t.TempDir(), "de-1996.hyd")
+	if err := os.WriteFile(path, compile(t, dict), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opened, err := hyphenate.OpenDictionary(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range benchWords {
+		if got, want := opened.Breaks(word), dict.Breaks(word); !reflect.DeepEqual(got, want) {
+			t.Errorf("breaks of %q: got %v, want %v", word, got, want)
+		}
+	}
+	if err := opened.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := opened.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if err := dict.Close(); err != nil {
+		t.Fatalf("Close of a dictionary which is not mapped: %v", err)
+	}
+	bad := filepath.Join(t.TempDir(), "bad.hyd")
+	if err := os.WriteFile(bad, []byte("HYPHDICT but not really"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hyphenate.OpenDictionary(bad); !errors.Is(err, hyphenate.ErrNotADictionary) && !errors.Is(err, hyphenate.ErrCorrupt) {
+		t.Fatalf("unexpected error for bad file: %v", err)
+	}
+}
+
+func BenchmarkOpenDictionary(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "de-1996.hyd")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := loadGerman(b).WriteTo(f); err != nil {
+		b.Fatal(err)
+	}
+	f.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dict, err := hyphenate.OpenDictionary(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		dict.Close()
+	}
+}
+
+func BenchmarkReadDictionary(b *testing.B) {
+	var buf bytes.Buffer
+	if _, err := loadGerman(b).WriteTo(&buf); err != nil {
+		b.Fatal(err)
+	}
+	data := buf.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := hyphenate.ReadDictionary(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Command hyphcheck evaluates the hyphenation of a dictionary against gold
+// lists of correctly hyphenated words (see package quality).
+//
+// Usage:
+//
+//	hyphcheck [-lang tag | -patterns file] [-exceptions file] [-worst n] gold ...
+//
+// Gold lists are TeX files with \hyphenation{...} blocks (*.tex) or lists with
+// one hyphenated word per line, like hyph-utf8's .hyp.txt files. Pattern files
+// are read according to their extension:
+//
+//	*.tex        TeX patterns and exceptions
+//	*.pat.txt    hyph-utf8 plain-text patterns
+//	*.dic        libhyphen (hunspell) dictionaries
+//	*.xml        Apache FOP hyphenation files
+//	*            compiled dictionaries (see hyphenate.ReadDictionary)
+//
+// The exit status is 1 if a dictionary or gold list cannot be read, and 2 for
+// invalid arguments.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/fop"
+	"github.com/npillmayer/hyphenate/hunspell"
+	"github.com/npillmayer/hyphenate/langs"
+	_ "github.com/npillmayer/hyphenate/langs/de1996"
+	_ "github.com/npillmayer/hyphenate/langs/enus"
+	"github.com/npillmayer/hyphenate/quality"
+	"github.com/npillmayer/hyphenate/tex"
+	"github.com/npillmayer/hyphenate/tex/texexceptions"
+	"github.com/npillmayer/hyphenate/txt"
+)
+
+func main() {
+	lang := flag.String("lang", "", "bundled language, e.g. de-1996")
+	patterns := flag.String("patterns", "", "pattern file")
+	exceptions := flag.String("exceptions", "", "additional exceptions (*.tex or .hyp.txt format)")
+	worst := flag.Int("worst", 20, "number of worst offenders to list")
+	flag.Parse()
+	if (*lang == "") == (*patterns == "") || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: hyphcheck [-lang tag | -patterns file] [-exceptions file] [-worst n] gold ...")
+		os.Exit(2)
+	}
+	if err := run(*lang, *patterns, *exceptions, *worst, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "hyphcheck: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(lang, patterns, exceptions string, worst int, gold []string) error {
+	var dict *hyphenate.Dictionary
+	var err error
+	if lang != "" {
+		dict, err = langs.Load(lang)
+	} else {
+		dict, err = loadPatterns(patterns)
+	}
+	if err != nil {
+		return err
+	}
+	if exceptions != "" {
+		if lang != "" { // do not change the dictionary shared by the registry
+			dict = dict.Clone()
+		}
+		if err = withExceptions(exceptions, dict.LoadExceptions); err != nil {
+			return err
+		}
+	}
+	for _, path := range gold {
+		var report *quality.Report
+		err = withExceptions(path, func(r hyphenate.ExceptionReader) (err error) {
+			report, err = quality.Evaluate(dict, r)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s:\n\n", path)
+		if err = report.WriteText(os.Stdout, worst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPatterns loads a dictionary according to the extension of path.
+func loadPatterns(path string) (*hyphenate.Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(path, ".tex"):
+		return tex.LoadDictionary(name, f)
+	case strings.HasSuffix(path, ".pat.txt"):
+		return txt.Load(name, txt.Files{Patterns: f})
+	case strings.HasSuffix(path, ".dic"):
+		return hunspell.LoadPatterns(name, f)
+	case strings.HasSuffix(path, ".xml"):
+		return fop.LoadDictionary(name, f)
+	}
+	return hyphenate.ReadDictionary(f)
+}
+
+// withExceptions calls f with a reader for the exceptions of a TeX file or of
+// a list with one hyphenated word per line.
+func withExceptions(path string, f func(hyphenate.ExceptionReader) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if strings.HasSuffix(path, ".tex") {
+		return f(texexceptions.NewReader(file))
+	}
+	return f(txt.NewExceptionReader(file))
+}
@@ -0,0 +1,101 @@
+package hyphenate
+
+import (
+	"slices"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CompoundReader may be implemented by a PatternReader for two-level pattern
+// sources, such as libhyphen dictionaries with a NEXTLEVEL directive.
+// LoadPatterns queries it after the pattern stream is exhausted.
+//
+// In a two-level dictionary, the patterns returned by Next find the boundaries
+// between the parts of compound words. The parts are then hyphenated
+// separately by the patterns of the second level, which NextLevel returns. For
+// single-level sources NextLevel returns nil. CompoundHyphenmins are the
+// minimum number of characters between a compound boundary and a hyphen
+// within a part (see Options.CompoundLeftMin); values <= 0 mean "unknown".
+//
+// Replacements of non-standard patterns are applied on the first level only.
+type CompoundReader interface {
+	NextLevel() PatternReader
+	CompoundHyphenmins() (left, right int)
+}
+
+// NoHyphenReader may be implemented by a PatternReader whose source forbids
+// breaks next to certain characters or character sequences, e.g. libhyphen's
+// NOHYPHEN directive. LoadPatterns queries it after the pattern stream is
+// exhausted.
+type NoHyphenReader interface {
+	NoHyphen() []string
+}
+
+// compoundScratch holds the buffers for hyphenating the parts of compound
+// words.
+type compoundScratch struct {
+	key    []uint16 // dense key of the dotted part
+	levels []int    // Liang levels of the dotted part
+	merged []int    // levels of the word
+}
+
+// compoundLevels combines the levels of a two-level dictionary. levels are
+// the levels of the first level for wordRunes, with len(wordRunes)+1 entries.
+// Odd levels mark compound boundaries, which are kept, while the levels
+// within the parts are computed from the patterns of the second level. Breaks
+// closer to a compound boundary than the compound hyphenmins of opts are
+// inhibited. The result is stored in s.merged.
+func (dict *Dictionary) compoundLevels(wordRunes []rune, levels []int, opts Options, s *compoundScratch) []int {
+	next := dict.nextLevel
+	n := len(wordRunes)
+	s.merged = resetLevels(s.merged, n+1)
+	start := 0
+	for end := 1; end <= n; end++ {
+		if end < n && levels[end]%2 == 0 {
+			continue
+		}
+		s.key = appendDottedKey(s.key[:0], next.patterns, wordRunes[start:end])
+		s.levels = matchPatterns(next.patterns, next.patternsV, s.key, s.levels, opts.Matcher)
+		part := s.levels[1 : end-start+2]
+		for i := 1; i < end-start; i++ {
+			if start > 0 && i < opts.CompoundLeftMin || end < n && end-start-i < opts.CompoundRightMin {
+				continue
+			}
+			s.merged[start+i] = part[i]
+		}
+		if start == 0 {
+			s.merged[0] = part[0]
+		}
+		if end == n {
+			s.merged[n] = part[end-start]
+		} else {
+			s.merged[end] = levels[end]
+		}
+		start = end
+	}
+	return s.merged
+}
+
+// inhibitNoHyphen clears the levels directly before and after every
+// occurrence of a NOHYPHEN sequence in wordRunes.
+func (dict *Dictionary) inhibitNoHyphen(wordRunes []rune, levels []int) {
+	for _, seq := range dict.noHyphen {
+		for i := 0; i+len(seq) <= len(wordRunes); i++ {
+			if slices.Equal(wordRunes[i:i+len(seq)], seq) {
+				levels[i], levels[i+len(seq)] = 0, 0
+			}
+		}
+	}
+}
+
+// setNoHyphen stores the NOHYPHEN sequences of a pattern source, in NFC and
+// folded to lowercase.
+func (dict *Dictionary) setNoHyphen(sequences []string) {
+	for _, seq := range sequences {
+		if seq == "" {
+			continue
+		}
+		lower, _ := dict.foldLetters(norm.NFC.String(seq))
+		dict.noHyphen = append(dict.noHyphen, []rune(lower))
+	}
+}
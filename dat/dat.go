@@ -11,9 +11,19 @@ package dat
 //   - Each payload record begins with a VarUint length L (number of digits),
 //     followed by ceil(L/2) bytes of packed digits.
 //
+// Failure links:
+//   - Fail, Output and Depth turn the trie into an Aho-Corasick automaton,
+//     which matches all patterns of a word in a single pass (see Next).
+//   - Fail[s] is the state of the longest proper suffix of s's path which is
+//     a path in the trie. Output[s] is the nearest state along the failure
+//     chain which ends a pattern, or 0. Depth[s] is the length of s's path.
+//   - The slices are empty if failure links have not been computed.
+//
 // Mapping:
-//   - Map is a BMP mapping from UTF-16 code unit (0..65535) to dense alphabet ID.
-//     0 means "not part of the pattern alphabet".
+//   - MapPaged is a BMP mapping from code points 0..65535 to dense alphabet IDs.
+//   - MapSupp maps code points of the supplementary planes (> 0xFFFF) to dense
+//     alphabet IDs. It is empty for BMP-only alphabets.
+//   - Dense ID 0 means "not part of the pattern alphabet".
 type DAT struct {
 	// Root state index (commonly 1).
 	Root uint32
@@ -38,13 +48,22 @@ type DAT struct {
 	//       byte = (d0<<4) | d1, digits are 0..9
 	Payload []byte
 
+	// Fail, Output and Depth are the Aho-Corasick failure links.
+	Fail   []int32 // len == N, or 0
+	Output []int32 // len == N, or 0
+	Depth  []uint8 // len == N, or 0; tries deeper than 255 are not supported
+
 	// MapBMP maps BMP code units to dense IDs [0..Sigma].
 	// For BMP-only workflows this is the fastest mapping.
 	// Memory: 65536 * 2 bytes = 128 KB per loaded language.
 	MapPaged PagedMapBMP
 
-	// Optional: MinLeft/MinRight are common hyphenation constraints.
-	// Keep them here if you want the hyphenator to be configured by the DAT.
+	// MapSupp maps code points above the BMP to dense IDs [0..Sigma].
+	// Lookup is slower than for MapPaged, but only needed for rare scripts.
+	MapSupp SparseMap
+
+	// MinLeft/MinRight are the hyphenmins of the pattern source. They serve
+	// as the default hyphenation constraints of a dictionary using this DAT.
 	MinLeft  uint8
 	MinRight uint8
 }
@@ -67,6 +86,55 @@ func (d *DAT) Transition(state uint32, dense uint16) (uint32, bool) {
 	return uint32(t), true
 }
 
+// HasFailureLinks reports whether the Aho-Corasick failure links are present.
+func (d *DAT) HasFailureLinks() bool {
+	return len(d.Base) > 0 && len(d.Fail) == len(d.Base) &&
+		len(d.Output) == len(d.Base) && len(d.Depth) == len(d.Base)
+}
+
+// Next is the goto function of the Aho-Corasick automaton: it returns the
+// state following state on dense, following failure links if there is no
+// direct transition. Returns Root if no suffix of the input matches a path of
+// the trie. Next requires failure links (see HasFailureLinks).
+func (d *DAT) Next(state uint32, dense uint16) uint32 {
+	if dense == 0 {
+		return d.Root
+	}
+	for {
+		if next, ok := d.Transition(state, dense); ok {
+			return next
+		}
+		if state == d.Root || int(state) >= len(d.Fail) {
+			return d.Root
+		}
+		state = uint32(d.Fail[state])
+	}
+}
+
 // Dense maps a BMP code unit to a dense alphabet ID.
 // Returns 0 if the code unit is not in the alphabet.
 func (d *DAT) Dense(bmp uint16) uint16 { return d.MapPaged.Dense(bmp) }
+
+// DenseRune maps a code point of the full Unicode range to a dense alphabet ID.
+// Returns 0 if the code point is not in the alphabet.
+func (d *DAT) DenseRune(r rune) uint16 {
+	if r < 0 {
+		return 0
+	}
+	if r <= 0xFFFF {
+		return d.MapPaged.Dense(uint16(r))
+	}
+	return d.MapSupp.Dense(r)
+}
+
+// SetDenseRune sets the mapping of code point r to a dense alphabet ID.
+func (d *DAT) SetDenseRune(r rune, dense uint16) {
+	if r < 0 {
+		return
+	}
+	if r <= 0xFFFF {
+		d.MapPaged.Set(uint16(r), dense)
+		return
+	}
+	d.MapSupp.Set(r, dense)
+}
@@ -59,3 +59,60 @@ func (m *PagedMapBMP) Set(bmp uint16, dense uint16) {
 	base := int(pi-1) << 8
 	m.Pages[base+int(bmp&0xFF)] = dense
 }
+
+// SparseMap maps code points outside the BMP (> 0xFFFF) to dense alphabet IDs.
+// Entries are kept sorted by code point and lookup is a binary search.
+//
+// Memory:
+//   - 6 bytes per entry; the zero value is an empty map and costs nothing,
+//     thus BMP-only alphabets do not pay for supplementary-plane support.
+type SparseMap struct {
+	Runes []rune   // sorted code points
+	IDs   []uint16 // dense IDs, parallel to Runes
+}
+
+// Dense returns the dense alphabet ID for code point r.
+// Returns 0 if absent.
+func (m *SparseMap) Dense(r rune) uint16 {
+	i, found := m.search(r)
+	if !found {
+		return 0
+	}
+	return m.IDs[i]
+}
+
+// Len returns the number of entries.
+func (m *SparseMap) Len() int { return len(m.Runes) }
+
+// Set sets mapping r -> dense (dense may be 0 to clear).
+func (m *SparseMap) Set(r rune, dense uint16) {
+	i, found := m.search(r)
+	switch {
+	case found && dense == 0:
+		m.Runes = append(m.Runes[:i], m.Runes[i+1:]...)
+		m.IDs = append(m.IDs[:i], m.IDs[i+1:]...)
+	case found:
+		m.IDs[i] = dense
+	case dense != 0:
+		m.Runes = append(m.Runes, 0)
+		m.IDs = append(m.IDs, 0)
+		copy(m.Runes[i+1:], m.Runes[i:])
+		copy(m.IDs[i+1:], m.IDs[i:])
+		m.Runes[i], m.IDs[i] = r, dense
+	}
+}
+
+// search returns the index of r in m.Runes, or the insertion index if r is
+// absent.
+func (m *SparseMap) search(r rune) (int, bool) {
+	lo, hi := 0, len(m.Runes)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if m.Runes[mid] < r {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(m.Runes) && m.Runes[lo] == r
+}
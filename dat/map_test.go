@@ -0,0 +1,49 @@
+package dat
+
+import "testing"
+
+func TestPagedMapBMP(t *testing.T) {
+	var m PagedMapBMP
+	m.Set('a', 1)
+	m.Set('ü', 2)
+	if m.Dense('a') != 1 || m.Dense('ü') != 2 || m.Dense('b') != 0 {
+		t.Fatalf("unexpected BMP mapping")
+	}
+	if m.NumPages() != 1 {
+		t.Fatalf("expected 1 page, got %d", m.NumPages())
+	}
+}
+
+func TestSparseMap(t *testing.T) {
+	var m SparseMap
+	runes := []rune{'𝐳', '𐌰', '𐐨', '𝐚', '𐌱'}
+	for i, r := range runes {
+		m.Set(r, uint16(i+1))
+	}
+	for i, r := range runes {
+		if d := m.Dense(r); d != uint16(i+1) {
+			t.Fatalf("dense ID of %U should be %d, is %d", r, i+1, d)
+		}
+	}
+	for i := 1; i < len(m.Runes); i++ {
+		if m.Runes[i-1] >= m.Runes[i] {
+			t.Fatalf("runes not sorted: %v", m.Runes)
+		}
+	}
+	m.Set('𐌰', 0)
+	if m.Dense('𐌰') != 0 || m.Len() != len(runes)-1 {
+		t.Fatalf("clearing an entry failed")
+	}
+}
+
+func TestDATDenseRune(t *testing.T) {
+	d := &DAT{Root: 1}
+	d.SetDenseRune('a', 1)
+	d.SetDenseRune('𐌰', 2)
+	if d.DenseRune('a') != 1 || d.DenseRune('𐌰') != 2 || d.DenseRune('𐌱') != 0 {
+		t.Fatalf("unexpected dense mapping")
+	}
+	if d.MapSupp.Len() != 1 {
+		t.Fatalf("BMP runes must not be stored in the supplementary map")
+	}
+}
@@ -11,6 +11,7 @@ import (
 type datBuildNode struct {
 	tmpID    int
 	state    uint32
+	terminal bool // a pattern ends at this node
 	children map[uint16]*datBuildNode
 }
 
@@ -47,18 +48,11 @@ func (db *datBackend) EncodeKey(s string) ([]uint16, bool) {
 	key := make([]uint16, 0, utf8.RuneCountInString(s))
 	if db.frozen {
 		for _, r := range s {
-			if r > 0xFFFF {
-				key = append(key, 0)
-				continue
-			}
-			key = append(key, db.compiled.Dense(uint16(r)))
+			key = append(key, db.compiled.DenseRune(r))
 		}
 		return key, true
 	}
 	for _, r := range s {
-		if r > 0xFFFF {
-			return nil, false
-		}
 		dense, ok := db.runeToDense[r]
 		if !ok {
 			if db.nextDenseID == ^uint16(0) {
@@ -67,13 +61,37 @@ func (db *datBackend) EncodeKey(s string) ([]uint16, bool) {
 			db.nextDenseID++
 			dense = db.nextDenseID
 			db.runeToDense[r] = dense
-			db.compiled.MapPaged.Set(uint16(r), dense)
+			db.compiled.SetDenseRune(r, dense)
 		}
 		key = append(key, dense)
 	}
 	return key, true
 }
 
+// EncodeRune maps r to its dense alphabet ID, or 0 if r is not part of the
+// alphabet. Valid for frozen tries only.
+func (db *datBackend) EncodeRune(r rune) uint16 {
+	return db.compiled.DenseRune(r)
+}
+
+// Root returns the root state of a frozen trie.
+func (db *datBackend) Root() int {
+	return int(db.compiled.Root)
+}
+
+// Step returns the state following state on symbol, or 0 if there is no such
+// transition. Valid for frozen tries only.
+func (db *datBackend) Step(state int, symbol uint16) int {
+	if symbol == 0 {
+		return 0
+	}
+	next, ok := db.compiled.Transition(uint32(state), symbol)
+	if !ok {
+		return 0
+	}
+	return int(next)
+}
+
 func (db *datBackend) AllocPositionForWord(key []uint16) int {
 	if len(key) == 0 {
 		return 0
@@ -95,6 +113,7 @@ func (db *datBackend) AllocPositionForWord(key []uint16) int {
 			}
 			n = child
 		}
+		n.terminal = true
 		return n.tmpID
 	}
 	state := db.compiled.Root
@@ -142,73 +161,89 @@ func (db *datBackend) Freeze() {
 		}
 	}
 	db.compiled.PayloadOff = make([]uint32, len(db.compiled.Base))
+	db.linkFailures(queue)
 	db.root = nil
 	db.runeToDense = nil
 	db.frozen = true
 }
 
-func (db *datBackend) ResolvePosition(pos int) int {
-	if pos <= 0 {
-		return 0
-	}
-	if !db.frozen {
-		return pos
+// linkFailures computes the Aho-Corasick failure links of the compiled trie.
+// queue holds the build nodes in breadth-first order, thus the links of a
+// node's failure chain are known before the node is visited.
+func (db *datBackend) linkFailures(queue []*datBuildNode) {
+	d := db.compiled
+	n := len(d.Base)
+	d.Fail = make([]int32, n)
+	d.Output = make([]int32, n)
+	d.Depth = make([]uint8, n)
+	terminal := make([]bool, n)
+	for _, node := range queue {
+		terminal[node.state] = node.terminal
 	}
-	if pos >= len(db.tmpToState) {
-		return 0
+	d.Fail[d.Root] = int32(d.Root)
+	for _, node := range queue {
+		for label, child := range node.children {
+			s, t := node.state, child.state
+			d.Depth[t] = d.Depth[s] + 1 // LoadPatterns limits patterns to 255 runes
+			fail := d.Root
+			if s != d.Root {
+				fail = d.Next(uint32(d.Fail[s]), label)
+			}
+			d.Fail[t] = int32(fail)
+			if terminal[fail] {
+				d.Output[t] = int32(fail)
+			} else {
+				d.Output[t] = d.Output[fail]
+			}
+		}
 	}
-	return int(db.tmpToState[pos])
 }
 
-func (db *datBackend) Iterator() patternIterator {
-	if db.frozen {
-		return &datIterator{
-			d:     db.compiled,
-			state: db.compiled.Root,
-		}
-	}
-	return &datBuildIterator{
-		node: db.root,
-	}
+// Next is the goto function of the Aho-Corasick automaton (see dat.DAT.Next).
+// Valid for frozen tries only.
+func (db *datBackend) Next(state int, symbol uint16) int {
+	return int(db.compiled.Next(uint32(state), symbol))
 }
 
-type datBuildIterator struct {
-	node *datBuildNode
-	dead bool
+// Output returns the nearest state along the failure chain of state which
+// ends a pattern, or 0.
+func (db *datBackend) Output(state int) int {
+	return int(db.compiled.Output[state])
 }
 
-func (it *datBuildIterator) Next(symbol uint16) int {
-	if it.dead || it.node == nil || symbol == 0 {
-		it.dead = true
-		return 0
-	}
-	next := it.node.children[symbol]
-	if next == nil {
-		it.dead = true
-		return 0
-	}
-	it.node = next
-	return next.tmpID
+// Depth returns the length of the path from the root to state.
+func (db *datBackend) Depth(state int) int {
+	return int(db.compiled.Depth[state])
+}
+
+// HasFailureLinks reports whether Next, Output and Depth may be used.
+func (db *datBackend) HasFailureLinks() bool {
+	return db.frozen && db.compiled.HasFailureLinks()
 }
 
-type datIterator struct {
-	d     *dat.DAT
-	state uint32
-	dead  bool
+// SetHyphenmins records the hyphenmins of the pattern source with the DAT.
+// Values are clamped to 1..255.
+func (db *datBackend) SetHyphenmins(left, right int) {
+	db.compiled.MinLeft = uint8(min(max(left, 1), 255))
+	db.compiled.MinRight = uint8(min(max(right, 1), 255))
 }
 
-func (it *datIterator) Next(symbol uint16) int {
-	if it.dead || it.d == nil || symbol == 0 {
-		it.dead = true
+// Hyphenmins returns the hyphenmins recorded with the DAT.
+func (db *datBackend) Hyphenmins() (left, right int) {
+	return int(db.compiled.MinLeft), int(db.compiled.MinRight)
+}
+
+func (db *datBackend) ResolvePosition(pos int) int {
+	if pos <= 0 {
 		return 0
 	}
-	next, ok := it.d.Transition(it.state, symbol)
-	if !ok {
-		it.dead = true
+	if !db.frozen {
+		return pos
+	}
+	if pos >= len(db.tmpToState) {
 		return 0
 	}
-	it.state = next
-	return int(next)
+	return int(db.tmpToState[pos])
 }
 
 func sortedLabels(children map[uint16]*datBuildNode) []uint16 {
@@ -0,0 +1,134 @@
+package hyphenate
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Default penalties for discretionaries, as in plain TeX.
+const (
+	DefaultHyphenPenalty   = 50 // cf. TeX's \hyphenpenalty
+	DefaultExHyphenPenalty = 50 // cf. TeX's \exhyphenpenalty
+)
+
+// Discretionary is a TeX-style discretionary break.
+//
+// If a line breaker chooses the break, the line ends with Pre and the next
+// line starts with Post. Otherwise NoBreak is typeset. Penalty is the cost of
+// taking the break.
+type Discretionary struct {
+	Pre     string // text ending the line, including the hyphen
+	Post    string // text starting the next line
+	NoBreak string // text if the break is not taken
+	Penalty int    // penalty for taking the break
+}
+
+// Fragment is an element of a word broken down for a typesetter: either a
+// fixed run of text or a discretionary break.
+type Fragment struct {
+	Text string         // fixed run of text; empty for discretionaries
+	Disc *Discretionary // discretionary break; nil for text runs
+}
+
+// Discretionaries breaks down word into a sequence of fixed text runs and
+// discretionaries, using the dictionary's default options.
+//
+// Example:
+//
+//	"table" => [ "ta", {Pre:"-" Post:"" NoBreak:"" Penalty:50}, "ble" ].
+func (dict *Dictionary) Discretionaries(word string) []Fragment {
+	if dict == nil {
+		return []Fragment{{Text: word}}
+	}
+	return dict.DiscretionariesWith(word, dict.Options)
+}
+
+// DiscretionariesWith breaks down word into a sequence of fixed text runs and
+// discretionaries, using options opts instead of the dictionary's default
+// options.
+//
+// Hyphenation opportunities become discretionaries {Pre: hyphen} with penalty
+// opts.HyphenPenalty. For non-standard breaks, Pre and Post carry the
+// replacement text and NoBreak the original text.
+//
+// Hard hyphens within the word ('-' and U+2010) separate word parts, which
+// are hyphenated independently. A hard hyphen becomes a discretionary
+// {Pre: "-", NoBreak: "-"} with penalty opts.ExHyphenPenalty. If
+// opts.RepeatHardHyphen is set, the hyphen is repeated at the start of the
+// next line, as is customary in Polish and Portuguese:
+//
+//	"ex-wife" => [ "ex", {Pre:"-" Post:"-" NoBreak:"-"}, "wife" ].
+//
+// Pattern sources carry no information about hard hyphens, so
+// RepeatHardHyphen is never set by the loaders; callers set it for the
+// languages which need it.
+func (dict *Dictionary) DiscretionariesWith(word string, opts Options) []Fragment {
+	hyphen := string(opts.hyphenChar())
+	var fragments []Fragment
+	var run strings.Builder
+	flush := func() {
+		if run.Len() > 0 {
+			fragments = append(fragments, Fragment{Text: run.String()})
+			run.Reset()
+		}
+	}
+	start := 0
+	for {
+		end, size := indexHardHyphen(word, start)
+		part := word[start:end]
+		prev := 0
+		for _, brk := range dict.BreaksWith(part, opts) {
+			from, to := brk.span()
+			if from < prev { // overlapping replacements
+				continue
+			}
+			run.WriteString(part[prev:from])
+			flush()
+			fragments = append(fragments, Fragment{Disc: &Discretionary{
+				Pre:     brk.Pre + hyphen,
+				Post:    brk.Post,
+				NoBreak: part[from:to],
+				Penalty: opts.HyphenPenalty,
+			}})
+			prev = to
+		}
+		run.WriteString(part[prev:])
+		if end == len(word) {
+			break
+		}
+		flush()
+		hard := word[end : end+size]
+		disc := &Discretionary{Pre: hard, NoBreak: hard, Penalty: opts.ExHyphenPenalty}
+		if opts.RepeatHardHyphen {
+			disc.Post = hard
+		}
+		fragments = append(fragments, Fragment{Disc: disc})
+		start = end + size
+	}
+	flush()
+	return fragments
+}
+
+// indexHardHyphen returns the byte offset and size of the first hard hyphen
+// in word at or after offset start, or len(word) if there is none. Hyphens
+// which do not have text on both sides do not count as hard hyphens.
+func indexHardHyphen(word string, start int) (int, int) {
+	for i, r := range word[start:] {
+		if r != '-' && r != '\u2010' {
+			continue
+		}
+		size := utf8.RuneLen(r)
+		if i > 0 && start+i+size < len(word) {
+			return start + i, size
+		}
+	}
+	return len(word), 0
+}
+
+// hyphenChar returns the hyphen character to use, defaulting to '-'.
+func (opts Options) hyphenChar() rune {
+	if opts.HyphenChar == 0 {
+		return '-'
+	}
+	return opts.HyphenChar
+}
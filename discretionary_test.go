@@ -0,0 +1,54 @@
+package hyphenate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscretionaries(t *testing.T) {
+	p, err := ParsePattern("c1k/k=k,1,2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, err := LoadPatterns("discretionaries", &slicePatternReader{entries: []Pattern{p}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.AddException("table", []int{0, 0, 1, 0, 0})
+	dict.AddException("wife", []int{0, 0, 0, 0})
+	hyphen := &Discretionary{Pre: "-", Penalty: DefaultHyphenPenalty}
+	if got, want := dict.Discretionaries("table"), []Fragment{
+		{Text: "ta"}, {Disc: hyphen}, {Text: "ble"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("table: got %v, want %v", got, want)
+	}
+	if got, want := dict.Discretionaries("Zucker"), []Fragment{
+		{Text: "Zu"},
+		{Disc: &Discretionary{Pre: "k-", Post: "k", NoBreak: "ck", Penalty: DefaultHyphenPenalty}},
+		{Text: "er"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Zucker: got %v, want %v", got, want)
+	}
+	hard := &Discretionary{Pre: "-", NoBreak: "-", Penalty: DefaultExHyphenPenalty}
+	if got, want := dict.Discretionaries("table-wife"), []Fragment{
+		{Text: "ta"}, {Disc: hyphen}, {Text: "ble"}, {Disc: hard}, {Text: "wife"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("table-wife: got %v, want %v", got, want)
+	}
+	opts := dict.Options
+	opts.RepeatHardHyphen = true
+	opts.HyphenChar = '\u2010'
+	opts.ExHyphenPenalty = 100
+	if got, want := dict.DiscretionariesWith("wife-table", opts), []Fragment{
+		{Text: "wife"},
+		{Disc: &Discretionary{Pre: "-", Post: "-", NoBreak: "-", Penalty: 100}},
+		{Text: "ta"},
+		{Disc: &Discretionary{Pre: "\u2010", Penalty: DefaultHyphenPenalty}},
+		{Text: "ble"},
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("wife-table with repeated hyphen: got %v, want %v", got, want)
+	}
+	if got := dict.Discretionaries("-table"); len(got) != 1 || got[0].Text != "-table" {
+		t.Fatalf("leading hyphen should not count as hard hyphen, got %v", got)
+	}
+}
@@ -9,8 +9,10 @@ a pattern file (available with the TeX distribution) and builds a frozen
 double-array trie (DAT) index. Hyphenation weight vectors are stored separately
 in a compact payload store and referenced by trie state IDs.
 
-The lookup path is Unicode-aware for BMP characters and supports non-ASCII
-patterns such as German umlauts.
+The lookup path is Unicode-aware for the full Unicode range and supports
+non-ASCII patterns such as German umlauts. Characters of the supplementary
+planes (above U+FFFF) are mapped through a separate sparse table, so
+BMP-only dictionaries do not pay for them.
 
 Further Reading
 
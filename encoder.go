@@ -0,0 +1,112 @@
+package hyphenate
+
+// Encoder renders a word together with its hyphenation opportunities for an
+// output target.
+type Encoder interface {
+	// Encode appends word to dst, with breaks marked in the target's syntax.
+	// Breaks are expected in ascending order, as returned by Dictionary.Breaks.
+	Encode(dst []byte, word string, breaks []Break) []byte
+}
+
+// Separator is an Encoder which inserts a fixed string at every break.
+// The text of the word is copied verbatim, i.e. it is not escaped.
+//
+// Non-standard breaks (see Replacement) cannot be expressed by a separator;
+// they are marked at their position, but the text of the word is left
+// unchanged.
+//
+// Example:
+//
+//	hyphenate.Separator("|") renders "table" as "ta|ble".
+type Separator string
+
+// Encode implements Encoder.
+func (sep Separator) Encode(dst []byte, word string, breaks []Break) []byte {
+	prev := 0
+	for _, brk := range breaks {
+		dst = append(dst, word[prev:brk.Byte]...)
+		dst = append(dst, sep...)
+		prev = brk.Byte
+	}
+	return append(dst, word[prev:]...)
+}
+
+// brokenForm is an Encoder which shows every break as taken, including the
+// text changes of non-standard breaks, e.g. "Schiffahrt" => "Schiff-fahrt".
+type brokenForm string
+
+func (sep brokenForm) Encode(dst []byte, word string, breaks []Break) []byte {
+	prev := 0
+	for _, brk := range breaks {
+		from, to := brk.span()
+		if from < prev {
+			continue
+		}
+		dst = append(dst, word[prev:from]...)
+		dst = append(dst, brk.Pre...)
+		dst = append(dst, sep...)
+		dst = append(dst, brk.Post...)
+		prev = to
+	}
+	return append(dst, word[prev:]...)
+}
+
+// texEncoder marks breaks with TeX's discretionary hyphen `\-`. Non-standard
+// breaks are written as `\discretionary{pre-}{post}{no-break}`.
+type texEncoder struct{}
+
+func (texEncoder) Encode(dst []byte, word string, breaks []Break) []byte {
+	prev := 0
+	for _, brk := range breaks {
+		from, to := brk.span()
+		if from < prev {
+			continue
+		}
+		dst = append(dst, word[prev:from]...)
+		if brk.IsNonStandard() {
+			dst = append(dst, `\discretionary{`...)
+			dst = append(dst, brk.Pre...)
+			dst = append(dst, `-}{`...)
+			dst = append(dst, brk.Post...)
+			dst = append(dst, `}{`...)
+			dst = append(dst, word[from:to]...)
+			dst = append(dst, '}')
+		} else {
+			dst = append(dst, `\-`...)
+		}
+		prev = to
+	}
+	return append(dst, word[prev:]...)
+}
+
+// groffEncoder marks breaks with groff's hyphenation character `\%`. A word
+// without breaks is prefixed with `\%`, which keeps groff from hyphenating it
+// on its own.
+type groffEncoder struct{}
+
+func (groffEncoder) Encode(dst []byte, word string, breaks []Break) []byte {
+	if len(breaks) == 0 {
+		dst = append(dst, `\%`...)
+		return append(dst, word...)
+	}
+	return Separator(`\%`).Encode(dst, word, breaks)
+}
+
+// Predefined encoders.
+var (
+	Hyphen     Encoder = brokenForm("-")     // plain hyphen, mainly for debugging
+	SoftHyphen Encoder = Separator("\u00AD") // Unicode soft hyphen U+00AD
+	HTMLEntity Encoder = Separator("&shy;")  // HTML soft hyphen entity
+	TeX        Encoder = texEncoder{}        // TeX discretionary hyphen
+	Groff      Encoder = groffEncoder{}      // groff hyphenation character
+)
+
+// Encode returns word with hyphenation opportunities marked by encoder enc,
+// using the dictionary's default options.
+//
+// Example:
+//
+//	dict.Encode("table", hyphenate.HTMLEntity) => "ta&shy;ble".
+func (dict *Dictionary) Encode(word string, enc Encoder) string {
+	return string(enc.Encode(make([]byte, 0, len(word)+8), word, dict.Breaks(word)))
+}
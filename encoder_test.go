@@ -0,0 +1,30 @@
+package hyphenate
+
+import "testing"
+
+func TestEncoders(t *testing.T) {
+	dict, err := LoadPatterns("encoders", &slicePatternReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.AddException("hyphenation", []int{0, 0, 1, 0, 0, 0, 1, 1, 0, 0, 0})
+	tests := []struct {
+		enc  Encoder
+		want string
+	}{
+		{enc: Hyphen, want: "hy-phen-a-tion"},
+		{enc: SoftHyphen, want: "hy\u00ADphen\u00ADa\u00ADtion"},
+		{enc: HTMLEntity, want: "hy&shy;phen&shy;a&shy;tion"},
+		{enc: TeX, want: `hy\-phen\-a\-tion`},
+		{enc: Groff, want: `hy\%phen\%a\%tion`},
+		{enc: Separator("·"), want: "hy·phen·a·tion"},
+	}
+	for _, tt := range tests {
+		if got := dict.Encode("hyphenation", tt.enc); got != tt.want {
+			t.Fatalf("encoding mismatch: got %q, want %q", got, tt.want)
+		}
+	}
+	if got := dict.Encode("word", Groff); got != `\%word` {
+		t.Fatalf("groff should suppress hyphenation of unbreakable words, got %q", got)
+	}
+}
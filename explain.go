@@ -0,0 +1,285 @@
+package hyphenate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Explanation tells how a dictionary hyphenates a word, see Explain.
+//
+// Offsets refer to the runes of Word, i.e. to the word in Unicode
+// normalization form NFC.
+type Explanation struct {
+	Word      string     // the word in NFC
+	Source    string     // name of the pattern source given to LoadPatterns
+	Matches   []Match    // patterns matching the word, by offset
+	Levels    []int      // levels of the patterns, as returned by Levels
+	Overrides []Override // changes of the patterns' result, in order of application
+	Exception bool       // the word is an exception
+	Breaks    []Break    // the resulting breaks, as returned by Breaks
+}
+
+// Match is a pattern matching a word. The pattern's first rune matches rune
+// Offset of the word, thus Pattern.Weights[k] contributes to the level before
+// rune Offset+k. Patterns starting with '.' have an offset of -1.
+type Match struct {
+	Pattern   Pattern
+	Offset    int
+	NextLevel bool // pattern of the second level of a two-level dictionary (see CompoundReader)
+	Line      int  // line of the pattern in the source, or 0 if unknown (see LineReader)
+}
+
+// Rule is a rule which overrides the levels of the patterns.
+type Rule uint8
+
+// Rules in the order of application.
+const (
+	CompoundMinRule   Rule = iota + 1 // too close to a compound boundary, see Options.CompoundLeftMin
+	NoHyphenRule                      // next to a NOHYPHEN sequence, see NoHyphenReader
+	LeftMinRule                       // too close to the start of the word, see Options.LeftMin
+	RightMinRule                      // too close to the end of the word, see Options.RightMin
+	CombiningMarkRule                 // before a combining mark
+	AllCapsRule                       // the word is in capitals, see Options.SkipAllCaps
+	ExceptionRule                     // the word is an exception
+)
+
+func (r Rule) String() string {
+	switch r {
+	case CompoundMinRule:
+		return "compound hyphenmin"
+	case NoHyphenRule:
+		return "NOHYPHEN"
+	case LeftMinRule:
+		return "left hyphenmin"
+	case RightMinRule:
+		return "right hyphenmin"
+	case CombiningMarkRule:
+		return "combining mark"
+	case AllCapsRule:
+		return "all caps"
+	case ExceptionRule:
+		return "exception"
+	}
+	return fmt.Sprintf("Rule(%d)", r)
+}
+
+// Override records a position where a rule changed the result of the
+// patterns, i.e. where it inhibited or, for exceptions, added a break.
+type Override struct {
+	Pos      int // position of the break, before rune Pos
+	Rule     Rule
+	From, To int // levels before and after the rule has been applied
+}
+
+// Explain tells how the dictionary hyphenates word with its default options:
+// which patterns match it, which levels they yield, and which rules and
+// exceptions override them. The breaks of the explanation are those of
+// Breaks. String renders the explanation in the style of the TeXbook's
+// hyphenation tables.
+//
+// Example:
+//
+//	fmt.Print(dict.Explain("hyphenation"))
+func (dict *Dictionary) Explain(word string) *Explanation {
+	opts := dict.Options
+	nfc := norm.NFC.String(word)
+	e := &Explanation{Word: nfc, Source: dict.source, Breaks: dict.BreaksWith(nfc, opts)}
+	lower, allCaps := dict.foldLetters(nfc)
+	wordRunes := []rune(lower)
+	n := len(wordRunes)
+	e.Levels = make([]int, n+1)
+	merged := e.Levels // levels after the compound hyphenmins
+	if dict.patterns != nil && dict.patternsV != nil {
+		e.Matches = dict.appendMatches(nil, wordRunes, 0, false)
+		e.Levels = dict.patternLevels(wordRunes)
+		merged = e.Levels
+		if dict.nextLevel != nil {
+			first := firstLevels(e.Matches, n)
+			e.Matches = dict.appendPartMatches(e.Matches, wordRunes, first)
+			merged = dict.compoundLevels(wordRunes, first, opts, &compoundScratch{})
+		}
+	}
+	levels := slices.Clone(e.Levels)
+	override := func(i int, rule Rule, level int) {
+		if levels[i]%2 != level%2 {
+			e.Overrides = append(e.Overrides, Override{Pos: i, Rule: rule, From: levels[i], To: level})
+		}
+		levels[i] = level
+	}
+	inhibited := slices.Clone(merged)
+	dict.inhibitNoHyphen(wordRunes, inhibited)
+	for i := 1; i < n; i++ {
+		override(i, CompoundMinRule, merged[i])
+		override(i, NoHyphenRule, inhibited[i])
+		switch {
+		case i < opts.LeftMin:
+			override(i, LeftMinRule, 0)
+		case n-i < opts.RightMin:
+			override(i, RightMinRule, 0)
+		case unicode.IsMark(wordRunes[i]):
+			override(i, CombiningMarkRule, 0)
+		}
+	}
+	if allCaps && opts.SkipAllCaps {
+		for i := 1; i < n; i++ {
+			override(i, AllCapsRule, 0)
+		}
+	} else if positions, found := dict.exceptions[lower]; found {
+		e.Exception = true
+		for i := 1; i < n; i++ {
+			if i < len(positions) && !unicode.IsMark(wordRunes[i]) {
+				override(i, ExceptionRule, positions[i])
+			} else {
+				override(i, ExceptionRule, 0)
+			}
+		}
+	}
+	return e
+}
+
+// appendMatches appends the patterns of dict which match the dotted word
+// wordRunes to matches. The first rune of wordRunes is located at offset of
+// the word explained.
+func (dict *Dictionary) appendMatches(matches []Match, wordRunes []rune, offset int, nextLevel bool) []Match {
+	tr, store := dict.patterns, dict.patternsV
+	dotted := append(append([]rune{'.'}, wordRunes...), '.')
+	key := appendDottedKey(nil, tr, wordRunes)
+	for i := range key {
+		state := tr.Root()
+		for j, c := range key[i:] {
+			if state = tr.Step(state, c); state == 0 {
+				break
+			}
+			packed, ok := store.Packed(state)
+			if !ok {
+				continue
+			}
+			seq := slices.Clone(dotted[i : i+j+1])
+			weights := make([]int, len(seq)+1)
+			for _, b := range packed {
+				if rel := int(b >> 4); rel < len(weights) {
+					weights[rel] = int(b & 0x0F)
+				}
+			}
+			if weights[len(seq)] == 0 {
+				weights = weights[:len(seq)]
+			}
+			matches = append(matches, Match{
+				Pattern:   Pattern{Sequence: seq, Weights: weights, Replacement: store.Replacement(state)},
+				Offset:    offset + i - 1,
+				NextLevel: nextLevel,
+				Line:      store.Line(state),
+			})
+		}
+	}
+	return matches
+}
+
+// appendPartMatches appends the matches of the second level of a two-level
+// dictionary for the parts of a compound word, which are separated at the
+// odd levels of first.
+func (dict *Dictionary) appendPartMatches(matches []Match, wordRunes []rune, first []int) []Match {
+	n := len(wordRunes)
+	start := 0
+	for end := 1; end <= n; end++ {
+		if end < n && first[end]%2 == 0 {
+			continue
+		}
+		matches = dict.nextLevel.appendMatches(matches, wordRunes[start:end], start, true)
+		start = end
+	}
+	return matches
+}
+
+// firstLevels computes the levels of the first-level matches for a word of n
+// runes.
+func firstLevels(matches []Match, n int) []int {
+	levels := make([]int, n+1)
+	for _, m := range matches {
+		for k, w := range m.Pattern.Weights {
+			if i := m.Offset + k; !m.NextLevel && i >= 0 && i <= n {
+				levels[i] = max(levels[i], w)
+			}
+		}
+	}
+	return levels
+}
+
+// String renders the explanation as a table. The first line shows the dotted
+// word, followed by a line per matching pattern, aligned to the word, and a
+// line with the combined levels:
+//
+//	 . h y p h e n a t i o n .
+//	   h y3p h                   hyph-en-us.tex:1837
+//	         h e2n               hyph-en-us.tex:1752
+//	         …
+//	                     o2n     hyph-en-us.tex:2955
+//	 . h0y3p0h0e2n5a4t2i0o2n .
+//	hy-phen-ation
+//
+// Overrides are listed before the resulting hyphenation, e.g.
+//
+//	exception: ta-ble (4 → 1)
+func (e *Explanation) String() string {
+	wordRunes := []rune(e.Word)
+	width := 2*len(wordRunes) + 5
+	var sb strings.Builder
+	row := func(cells []rune, note string) {
+		line := strings.TrimRight(string(cells), " ")
+		if note != "" {
+			line += strings.Repeat(" ", max(width-len([]rune(line)), 0)+2) + note
+		}
+		sb.WriteString(line + "\n")
+	}
+	// blank returns an empty row; the rune before dotted rune j is located in
+	// column 2j, the rune itself in column 2j+1
+	blank := func() []rune {
+		cells := make([]rune, width)
+		for i := range cells {
+			cells[i] = ' '
+		}
+		return cells
+	}
+	word := blank()
+	for j, r := range append(append([]rune{'.'}, wordRunes...), '.') {
+		word[2*j+1] = r
+	}
+	row(word, "")
+	for _, m := range e.Matches {
+		cells := blank()
+		start := m.Offset + 1 // in the dotted word
+		for k, r := range m.Pattern.Sequence {
+			cells[2*(start+k)+1] = r
+		}
+		for k, w := range m.Pattern.Weights {
+			if w > 0 {
+				cells[2*(start+k)] = rune('0' + w)
+			}
+		}
+		var notes []string
+		if m.Line > 0 {
+			notes = append(notes, fmt.Sprintf("%s:%d", e.Source, m.Line))
+		}
+		if m.NextLevel {
+			notes = append(notes, "(second level)")
+		}
+		if rep := m.Pattern.Replacement; rep != nil {
+			notes = append(notes, m.Pattern.String())
+		}
+		row(cells, strings.Join(notes, " "))
+	}
+	for i := 1; i < len(wordRunes); i++ {
+		word[2*(i+1)] = rune('0' + e.Levels[i])
+	}
+	row(word, "")
+	for _, o := range e.Overrides {
+		fmt.Fprintf(&sb, "%s: %s-%s (%d → %d)\n", o.Rule, string(wordRunes[:o.Pos]),
+			string(wordRunes[o.Pos:]), o.From, o.To)
+	}
+	sb.WriteString(strings.Join(splitAtBreaks(e.Word, e.Breaks), "-") + "\n")
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package hyphenate_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/hunspell"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+func TestExplain(t *testing.T) {
+	dict := loadGerman(t)
+	e := dict.Explain("Abbildung")
+	if !reflect.DeepEqual(e.Levels, dict.Levels("Abbildung")) {
+		t.Fatalf("levels should be %v, are %v", dict.Levels("Abbildung"), e.Levels)
+	}
+	if !reflect.DeepEqual(e.Breaks, dict.Breaks("Abbildung")) {
+		t.Fatalf("breaks should be %v, are %v", dict.Breaks("Abbildung"), e.Breaks)
+	}
+	found := false
+	for _, m := range e.Matches {
+		if m.Pattern.String() == "3bil" {
+			found = m.Offset == 2 && m.Line == 4646
+		}
+	}
+	if !found {
+		t.Fatalf("pattern 3bil should match at offset 2 from line 4646, matches are %v", e.Matches)
+	}
+	want := []hyphenate.Override{{Pos: 8, Rule: hyphenate.RightMinRule, From: 1, To: 0}}
+	if !reflect.DeepEqual(e.Overrides, want) {
+		t.Fatalf("overrides should be %v, are %v", want, e.Overrides)
+	}
+	s := e.String()
+	for _, row := range []string{
+		" . A b b i l d u n g .\n",
+		"      3b i l             hyph-de-1996.tex:4646\n",
+		" . A2b3b2i2l1d0u2n1g .\n",
+		"right hyphenmin: Abbildun-g (1 → 0)\n",
+		"Ab-bil-dung\n",
+	} {
+		if !strings.Contains(s, row) {
+			t.Fatalf("explanation should contain %q, is\n%s", row, s)
+		}
+	}
+}
+
+func TestExplainExceptions(t *testing.T) {
+	src := "\\patterns{\n1b\n4l\n}\n\\hyphenation{\ntab-le\n}\n"
+	dict, err := tex.LoadDictionary("test.tex", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := dict.Explain("table")
+	if len(e.Matches) != 2 || e.Matches[0].Offset != 2 || e.Matches[0].Line != 2 || e.Matches[1].Line != 3 {
+		t.Fatalf("expected matches 1b at offset 2 from line 2 and 4l from line 3, are %v", e.Matches)
+	}
+	want := []hyphenate.Override{
+		{Pos: 2, Rule: hyphenate.ExceptionRule, From: 1, To: 0},
+		{Pos: 3, Rule: hyphenate.ExceptionRule, From: 4, To: 1},
+	}
+	if !e.Exception || !reflect.DeepEqual(e.Overrides, want) {
+		t.Fatalf("overrides should be %v, are %v", want, e.Overrides)
+	}
+	dict.Options.SkipAllCaps = true
+	e = dict.Explain("CABLE")
+	want = []hyphenate.Override{{Pos: 2, Rule: hyphenate.AllCapsRule, From: 1, To: 0}}
+	if len(e.Breaks) != 0 || !reflect.DeepEqual(e.Overrides, want) {
+		t.Fatalf("overrides should be %v, are %v", want, e.Overrides)
+	}
+	// compiled dictionaries keep source lines on request only
+	compiled, err := hyphenate.ReadDictionary(bytes.NewReader(compile(t, dict)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e = compiled.Explain("table")
+	if len(e.Matches) != 2 || e.Matches[0].Line != 0 || e.Source != "" {
+		t.Fatalf("expected matches without lines, are %v from %q", e.Matches, e.Source)
+	}
+	if s := e.String(); strings.Contains(s, ":0") {
+		t.Fatalf("explanation should not contain lines, is\n%s", s)
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteCompiled(&buf, hyphenate.CompileOptions{SourceLines: true}); err != nil {
+		t.Fatal(err)
+	}
+	if compiled, err = hyphenate.ReadDictionary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	e = compiled.Explain("table")
+	if len(e.Matches) != 2 || e.Matches[0].Line != 2 || e.Matches[1].Line != 3 || e.Source != "test.tex" {
+		t.Fatalf("expected matches from test.tex, lines 2 and 3, are %v from %q", e.Matches, e.Source)
+	}
+}
+
+func TestExplainCompoundAndNoHyphen(t *testing.T) {
+	src := "UTF-8\nCOMPOUNDLEFTHYPHENMIN 3\nNOHYPHEN x\n1h\nNEXTLEVEL\n1b\n"
+	dict, err := hunspell.LoadPatterns("test.dic", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := dict.Explain("abhabab")
+	want := []hyphenate.Override{
+		{Pos: 1, Rule: hyphenate.LeftMinRule, From: 1, To: 0},
+		{Pos: 4, Rule: hyphenate.CompoundMinRule, From: 1, To: 0},
+		{Pos: 6, Rule: hyphenate.RightMinRule, From: 1, To: 0},
+	}
+	if !reflect.DeepEqual(e.Overrides, want) {
+		t.Fatalf("overrides should be %v, are %v", want, e.Overrides)
+	}
+	next := 0
+	for _, m := range e.Matches {
+		if m.NextLevel {
+			next++
+		}
+	}
+	if len(e.Matches) != 4 || next != 3 {
+		t.Fatalf("expected 1 match of the first and 3 of the second level, are %v", e.Matches)
+	}
+	if h := strings.Join(dict.Hyphenate("abhabab"), "-"); h != "ab-habab" {
+		t.Fatalf("abhabab should be ab-habab, is %s", h)
+	}
+	e = dict.Explain("abxbab")
+	want = []hyphenate.Override{
+		{Pos: 1, Rule: hyphenate.LeftMinRule, From: 1, To: 0},
+		{Pos: 3, Rule: hyphenate.NoHyphenRule, From: 1, To: 0},
+		{Pos: 5, Rule: hyphenate.RightMinRule, From: 1, To: 0},
+	}
+	if !reflect.DeepEqual(e.Overrides, want) {
+		t.Fatalf("overrides should be %v, are %v", want, e.Overrides)
+	}
+}
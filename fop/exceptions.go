@@ -0,0 +1,137 @@
+package fop
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/npillmayer/hyphenate"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ExceptionReader streams the exceptions of an FOP hyphenation file.
+type ExceptionReader struct {
+	doc     *document
+	queue   []exception
+	current exception                      // exception being read
+	reps    map[int]*hyphenate.Replacement // of the exception most recently returned
+}
+
+// exception is an exception word with the positions of its breaks.
+type exception struct {
+	word   []rune
+	breaks []int
+	reps   map[int]*hyphenate.Replacement
+}
+
+// LoadExceptions parses the exceptions of an FOP hyphenation file and adds
+// them to dict.
+func LoadExceptions(dict *hyphenate.Dictionary, reader io.Reader) error {
+	return dict.LoadExceptions(NewExceptionReader(reader))
+}
+
+// NewExceptionReader creates a reader for the exceptions of an FOP
+// hyphenation file. Exceptions are separated by white space, with breaks
+// marked by the hyphen character (see element hyphen-char) or by elements
+//
+//	<hyphen pre="k-" no="c" post=""/>
+//
+// for non-standard breaks: no is the text of the word if the break is not
+// taken, pre ends the line and post starts the next line if it is. A
+// trailing hyphen character of pre is dropped, as hyphenate adds the hyphen
+// itself. The reader implements hyphenate.ExceptionReplacementReader.
+func NewExceptionReader(reader io.Reader) *ExceptionReader {
+	return &ExceptionReader{doc: newDocument(reader)}
+}
+
+// Next returns the next exception as (word, positions).
+// It returns io.EOF when exhausted.
+func (r *ExceptionReader) Next() (string, []int, error) {
+	for len(r.queue) == 0 {
+		tok, err := r.doc.next()
+		if err == io.EOF {
+			r.finish()
+			if len(r.queue) == 0 {
+				return "", nil, io.EOF
+			}
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			if r.doc.section == "exceptions" {
+				r.text(string(t))
+			}
+		case xml.StartElement:
+			r.hyphen(t)
+		case xml.EndElement:
+			if t.Name.Local == "exceptions" {
+				r.finish()
+			}
+		}
+	}
+	e := r.queue[0]
+	r.queue = r.queue[1:]
+	positions := make([]int, len(e.word))
+	for _, b := range e.breaks {
+		if b > 0 && b < len(positions) {
+			positions[b] = 1
+		}
+	}
+	r.reps = e.reps
+	return string(e.word), positions, nil
+}
+
+// Replacements returns the replacements of the non-standard breaks of the
+// exception most recently returned by Next, by position. It returns nil for
+// standard exceptions.
+func (r *ExceptionReader) Replacements() map[int]*hyphenate.Replacement {
+	return r.reps
+}
+
+// text adds text to the current exception.
+func (r *ExceptionReader) text(text string) {
+	for _, c := range norm.NFC.String(text) {
+		switch {
+		case unicode.IsSpace(c):
+			r.finish()
+		case c == r.doc.hyphenChar:
+			r.current.breaks = append(r.current.breaks, len(r.current.word))
+		default:
+			r.current.word = append(r.current.word, c)
+		}
+	}
+}
+
+// hyphen adds a break from element hyphen to the current exception.
+func (r *ExceptionReader) hyphen(elem xml.StartElement) {
+	pre := strings.TrimSuffix(attr(elem, "pre"), string(r.doc.hyphenChar))
+	no, post := norm.NFC.String(attr(elem, "no")), attr(elem, "post")
+	e := &r.current
+	pos := len(e.word)
+	e.breaks = append(e.breaks, pos)
+	if pre != "" || no != "" || post != "" {
+		if e.reps == nil {
+			e.reps = make(map[int]*hyphenate.Replacement)
+		}
+		e.reps[pos] = &hyphenate.Replacement{
+			Pre:   pre,
+			Post:  post,
+			Start: pos,
+			Cut:   utf8.RuneCountInString(no),
+		}
+	}
+	e.word = append(e.word, []rune(no)...)
+}
+
+// finish completes the current exception, if any.
+func (r *ExceptionReader) finish() {
+	if len(r.current.word) > 0 {
+		r.queue = append(r.queue, r.current)
+	}
+	r.current = exception{}
+}
@@ -0,0 +1,160 @@
+// Package fop reads the hyphenation files of Apache FOP (XML documents with
+// root element hyphenation-info).
+package fop
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/npillmayer/hyphenate"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// LoadDictionary loads the patterns and exceptions of an FOP hyphenation file.
+//
+// A hyphenation file has the form
+//
+//	<hyphenation-info>
+//	  <hyphen-char value="-"/>
+//	  <hyphen-min before="2" after="2"/>
+//	  <classes>
+//	    aA bB cC ... äÄ
+//	  </classes>
+//	  <exceptions>
+//	    ta-ble Zu<hyphen pre="k-" no="c" post=""/>ker
+//	  </exceptions>
+//	  <patterns>
+//	    .ach4 .ad4der .af1t ...
+//	  </patterns>
+//	</hyphenation-info>
+//
+// This will load the file temporarily into memory.
+func LoadDictionary(name string, reader io.Reader) (*hyphenate.Dictionary, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	dict, err := LoadPatterns(name, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	err = LoadExceptions(dict, bytes.NewReader(data))
+	return dict, err
+}
+
+// document walks the sections of a hyphenation file. The settings in
+// elements hyphen-char, hyphen-min and classes are collected on the way.
+type document struct {
+	dec        *xml.Decoder
+	section    string // "classes", "exceptions", "patterns" or ""
+	hyphenChar rune
+	leftMin    int
+	rightMin   int
+	letters    map[rune]rune // from classes
+}
+
+func newDocument(reader io.Reader) *document {
+	dec := xml.NewDecoder(reader)
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		enc, err := ianaindex.IANA.Encoding(label)
+		if err != nil || enc == nil {
+			return nil, fmt.Errorf("unsupported encoding %q", label)
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return &document{dec: dec, hyphenChar: '-'}
+}
+
+// next returns the next token within the sections for exceptions and
+// patterns. Text is returned as a copy. next returns io.EOF at the end of
+// the document.
+func (d *document) next() (xml.Token, error) {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch name := t.Name.Local; name {
+			case "classes", "exceptions", "patterns":
+				d.section = name
+			case "hyphen-char":
+				if r, _ := utf8.DecodeRuneInString(attr(t, "value")); r != utf8.RuneError {
+					d.hyphenChar = r
+				}
+			case "hyphen-min":
+				d.leftMin, _ = strconv.Atoi(attr(t, "before"))
+				d.rightMin, _ = strconv.Atoi(attr(t, "after"))
+			case "hyphen":
+				if d.section == "exceptions" {
+					return t.Copy(), nil
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == d.section {
+				d.section = ""
+				return t, nil
+			}
+		case xml.CharData:
+			switch d.section {
+			case "classes":
+				d.classes(string(t))
+			case "exceptions", "patterns":
+				return t.Copy(), nil
+			}
+		}
+	}
+}
+
+// classes records the character classes of text. The first character of a
+// class is its lowercase form, e.g. "aA" or "äÄ".
+func (d *document) classes(text string) {
+	for _, class := range strings.Fields(text) {
+		lower, _ := utf8.DecodeRuneInString(class)
+		if d.letters == nil {
+			d.letters = make(map[rune]rune)
+		}
+		for _, r := range class {
+			d.letters[r] = lower
+		}
+	}
+}
+
+// words splits text, which the decoder has just read, into words.
+func (d *document) words(text string) []word {
+	var words []word
+	line := d.line() - strings.Count(text, "\n")
+	for i, l := range strings.Split(text, "\n") {
+		for _, f := range strings.Fields(l) {
+			words = append(words, word{text: f, line: line + i})
+		}
+	}
+	return words
+}
+
+// word is a white space separated word of a text, with its line.
+type word struct {
+	text string
+	line int
+}
+
+// line returns the current line of the input.
+func (d *document) line() int {
+	line, _ := d.dec.InputPos()
+	return line
+}
+
+func attr(elem xml.StartElement, name string) string {
+	for _, a := range elem.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package fop
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+const fixture = `<?xml version="1.0" encoding="utf-8"?>
+<!DOCTYPE hyphenation-info SYSTEM "hyphenation.dtd">
+<hyphenation-info>
+<hyphen-char value="-"/>
+<hyphen-min before="1" after="3"/>
+<!-- classes, exceptions and patterns for testing -->
+<classes>
+aA bB cC eE
+kK lL rR tT uU zZ
+äÄ ''
+</classes>
+<exceptions>
+ta-ble
+Zu<hyphen pre="k-" no="c" post=""/>ker
+</exceptions>
+<patterns>
+1ba  a1b
+.zu1
+</patterns>
+</hyphenation-info>
+`
+
+func TestPatternReader(t *testing.T) {
+	r := NewPatternReader(strings.NewReader(fixture))
+	var patterns []string
+	for {
+		seq, weights, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		patterns = append(patterns, hyphenate.Pattern{Sequence: seq, Weights: weights}.String())
+	}
+	if want := []string{"1ba", "a1b", ".zu1"}; !reflect.DeepEqual(patterns, want) {
+		t.Fatalf("patterns should be %v, are %v", want, patterns)
+	}
+	if left, right := r.Hyphenmins(); left != 1 || right != 3 {
+		t.Fatalf("hyphenmins should be 1/3, are %d/%d", left, right)
+	}
+	letters := r.Letters()
+	if letters['Ä'] != 'ä' || letters['a'] != 'a' || letters['\''] != '\'' {
+		t.Fatalf("letters are %v", letters)
+	}
+}
+
+func TestPatternReaderError(t *testing.T) {
+	src := "<hyphenation-info>\n<patterns>\na1b\n\n  a12b\n</patterns>\n</hyphenation-info>"
+	r := NewPatternReader(strings.NewReader(src))
+	if _, _, err := r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if _, _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("bad pattern should be reported for line 5, error is %v", err)
+	}
+}
+
+func TestExceptionReader(t *testing.T) {
+	r := NewExceptionReader(strings.NewReader(fixture))
+	word, positions, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if word != "table" || !reflect.DeepEqual(positions, []int{0, 0, 1, 0, 0}) || r.Replacements() != nil {
+		t.Fatalf("first exception should be table [0 0 1 0 0], is %s %v", word, positions)
+	}
+	word, positions, err = r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if word != "Zucker" || !reflect.DeepEqual(positions, []int{0, 0, 1, 0, 0, 0}) {
+		t.Fatalf("second exception should be Zucker [0 0 1 0 0 0], is %s %v", word, positions)
+	}
+	want := map[int]*hyphenate.Replacement{2: {Pre: "k", Start: 2, Cut: 1}}
+	if reps := r.Replacements(); !reflect.DeepEqual(reps, want) {
+		t.Fatalf("replacements of Zucker should be %v, are %v", want, reps)
+	}
+	if _, _, err = r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	dict, err := LoadDictionary("fop", strings.NewReader(fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := hyphenate.ReadDictionary(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"table":   "ta-ble",
+		"Zucker":  "Zuk-ker",
+		"ZUCKER":  "ZUK-KER",
+		"zubabab": "zu-ba-bab",
+		"Zuber":   "Zu-ber",
+	}
+	for _, d := range []*hyphenate.Dictionary{dict, compiled} {
+		for word, want := range tests {
+			if h := d.HyphenationString(word); h != want {
+				t.Errorf("%s should be %s, is %s", word, want, h)
+			}
+		}
+		if !d.IsLetter('\'') {
+			t.Errorf("class ' should be a letter")
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	src := "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+		"<hyphenation-info><patterns>\xe41b</patterns></hyphenation-info>"
+	r := NewPatternReader(strings.NewReader(src))
+	seq, _, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if string(seq) != "äb" {
+		t.Fatalf("pattern should be äb, is %s", string(seq))
+	}
+}
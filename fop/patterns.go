@@ -0,0 +1,83 @@
+package fop
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// PatternReader streams the patterns of an FOP hyphenation file.
+type PatternReader struct {
+	doc         *document
+	pending     []word // patterns of the current text
+	replacement *hyphenate.Replacement
+	line        int // of the most recent pattern
+}
+
+// LoadPatterns parses the patterns of an FOP hyphenation file and returns a
+// ready-to-use dictionary. Exceptions are not loaded, see LoadDictionary.
+func LoadPatterns(name string, reader io.Reader) (*hyphenate.Dictionary, error) {
+	return hyphenate.LoadPatterns(name, NewPatternReader(reader))
+}
+
+// NewPatternReader creates a reader for the patterns of an FOP hyphenation
+// file. Patterns are separated by white space.
+//
+// The reader implements hyphenate.HyphenminsReader for element hyphen-min,
+// hyphenate.LetterReader for the character classes and hyphenate.LineReader.
+func NewPatternReader(reader io.Reader) *PatternReader {
+	return &PatternReader{doc: newDocument(reader)}
+}
+
+// Next returns the next pattern as (sequence, weights).
+// It returns io.EOF when exhausted.
+func (r *PatternReader) Next() ([]rune, []int, error) {
+	for len(r.pending) == 0 {
+		tok, err := r.doc.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if text, ok := tok.(xml.CharData); ok && r.doc.section == "patterns" {
+			r.pending = r.doc.words(string(text))
+		}
+	}
+	w := r.pending[0]
+	r.pending = r.pending[1:]
+	p, err := hyphenate.ParsePattern(w.text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", w.line, err)
+	}
+	r.replacement, r.line = p.Replacement, w.line
+	return p.Sequence, p.Weights, nil
+}
+
+// Line returns the line of the pattern most recently returned by Next.
+func (r *PatternReader) Line() int {
+	return r.line
+}
+
+// Replacement returns the replacement of the pattern most recently returned
+// by Next if it is a non-standard pattern, and nil otherwise.
+func (r *PatternReader) Replacement() *hyphenate.Replacement {
+	return r.replacement
+}
+
+// Hyphenmins returns the values of element hyphen-min, i.e.
+//
+//	<hyphen-min before="2" after="3"/>
+//
+// Values are 0 if the file does not specify them. Hyphenmins should be called
+// after Next has returned io.EOF.
+func (r *PatternReader) Hyphenmins() (left, right int) {
+	return r.doc.leftMin, r.doc.rightMin
+}
+
+// Letters returns the letters of the character classes, with their lowercase
+// forms. The first character of a class is its lowercase form, e.g. "aA" maps
+// 'A' to 'a'. Like Hyphenmins, Letters should be called after Next has
+// returned io.EOF.
+func (r *PatternReader) Letters() map[rune]rune {
+	return r.doc.letters
+}
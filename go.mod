@@ -2,6 +2,9 @@ module github.com/npillmayer/hyphenate
 
 go 1.25.6
 
-require github.com/npillmayer/schuko v0.2.0-alpha.3
+require (
+	github.com/npillmayer/schuko v0.2.0-alpha.3
+	golang.org/x/text v0.36.0
+)
 
 require github.com/davecgh/go-spew v1.1.1 // indirect
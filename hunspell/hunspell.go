@@ -0,0 +1,229 @@
+// Package hunspell reads the hyphenation dictionaries of libhyphen, as
+// shipped with LibreOffice, Firefox and Chromium (hyph_xx_YY.dic).
+package hunspell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/npillmayer/hyphenate"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// Reader streams the patterns of a libhyphen dictionary. A dictionary has the
+// form
+//
+//	ISO8859-1
+//	LEFTHYPHENMIN 2
+//	RIGHTHYPHENMIN 2
+//	NOHYPHEN -,'
+//	.a2b
+//	.ab1s
+//	 ...
+//
+// The first line names the character set of the file. One pattern per line
+// follows, in Liang's notation or as non-standard pattern in libhyphen syntax
+// (see hyphenate.ParsePattern), interspersed with directives. Lines starting
+// with '%' or '#' are comments.
+//
+// A NEXTLEVEL directive starts the second level of a two-level dictionary: the
+// patterns before it find compound boundaries, the patterns after it
+// hyphenate the parts of compounds (see hyphenate.CompoundReader). The
+// second level is read by the reader returned from NextLevel.
+type Reader struct {
+	src         *source
+	next        *Reader // reader for the next level, after NEXTLEVEL
+	done        bool
+	replacement *hyphenate.Replacement // of the most recent non-standard pattern
+}
+
+// source holds the input and the directives shared by the levels of a
+// dictionary.
+type source struct {
+	scanner          *bufio.Scanner
+	line             int
+	charset          string
+	leftMin          int
+	rightMin         int
+	compoundLeftMin  int
+	compoundRightMin int
+	noHyphen         []string
+}
+
+// LoadPatterns parses a libhyphen dictionary and returns a ready-to-use
+// hyphenation dictionary.
+func LoadPatterns(name string, reader io.Reader) (*hyphenate.Dictionary, error) {
+	r, err := NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	return hyphenate.LoadPatterns(name, r)
+}
+
+// NewReader creates a reader for a libhyphen dictionary. It reads the
+// character set from the first line and decodes the rest of the input
+// accordingly. Unknown character sets are an error.
+func NewReader(reader io.Reader) (*Reader, error) {
+	br := bufio.NewReader(reader)
+	first, err := br.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	charset := strings.TrimSpace(strings.TrimPrefix(first, "\ufeff"))
+	if charset == "" {
+		return nil, fmt.Errorf("missing character set")
+	}
+	enc, err := encodingByName(charset)
+	if err != nil {
+		return nil, err
+	}
+	var input io.Reader = br
+	if enc != nil {
+		input = enc.NewDecoder().Reader(br)
+	}
+	src := &source{
+		scanner: bufio.NewScanner(input),
+		line:    1,
+		charset: charset,
+	}
+	return &Reader{src: src}, nil
+}
+
+var isoCharset = regexp.MustCompile(`^(?i:iso)[-_]?(8859)[-_](\d+)$`)
+
+// encodingByName returns the encoding for a character set name as used by
+// libhyphen dictionaries, e.g. "ISO8859-1", "KOI8-R" or "microsoft-cp1251".
+// UTF-8 yields a nil encoding.
+func encodingByName(charset string) (encoding.Encoding, error) {
+	name := charset
+	switch lower := strings.ToLower(charset); {
+	case lower == "utf-8" || lower == "utf8":
+		return nil, nil
+	case isoCharset.MatchString(charset):
+		name = isoCharset.ReplaceAllString(charset, "ISO-$1-$2")
+	case strings.HasPrefix(lower, "microsoft-cp"):
+		name = "windows-" + strings.TrimPrefix(lower, "microsoft-cp")
+	case strings.HasPrefix(lower, "tis620"):
+		name = "TIS-620"
+	}
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil || enc == nil {
+		return nil, fmt.Errorf("unsupported character set %q", charset)
+	}
+	return enc, nil
+}
+
+// Charset returns the name of the character set from the first line of the
+// dictionary.
+func (r *Reader) Charset() string {
+	return r.src.charset
+}
+
+// Next returns the next pattern as (sequence, weights), processing the
+// directives on the way. It returns io.EOF when the input or the level is
+// exhausted. Patterns are expected one per line; further text on a pattern
+// line is an error.
+func (r *Reader) Next() ([]rune, []int, error) {
+	src := r.src
+	for !r.done && src.scanner.Scan() {
+		src.line++
+		fields := strings.Fields(src.scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "NEXTLEVEL":
+			r.done, r.next = true, &Reader{src: src}
+			return nil, nil, io.EOF
+		case "LEFTHYPHENMIN":
+			src.leftMin, err = argument(fields)
+		case "RIGHTHYPHENMIN":
+			src.rightMin, err = argument(fields)
+		case "COMPOUNDLEFTHYPHENMIN":
+			src.compoundLeftMin, err = argument(fields)
+		case "COMPOUNDRIGHTHYPHENMIN":
+			src.compoundRightMin, err = argument(fields)
+		case "NOHYPHEN":
+			if len(fields) > 1 {
+				src.noHyphen = append(src.noHyphen, strings.Split(fields[1], ",")...)
+			}
+		default:
+			if len(fields) > 1 {
+				return nil, nil, fmt.Errorf("line %d: unexpected %q after pattern %q", src.line, fields[1], fields[0])
+			}
+			p, err := hyphenate.ParsePattern(fields[0])
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", src.line, err)
+			}
+			r.replacement = p.Replacement
+			return p.Sequence, p.Weights, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", src.line, err)
+		}
+	}
+	if err := src.scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	r.done = true
+	return nil, nil, io.EOF
+}
+
+// argument returns the numeric argument of a directive.
+func argument(fields []string) (int, error) {
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("directive %s without value", fields[0])
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("directive %s: %w", fields[0], err)
+	}
+	return n, nil
+}
+
+// Replacement returns the replacement of the pattern most recently returned
+// by Next if it is a non-standard pattern, and nil otherwise.
+func (r *Reader) Replacement() *hyphenate.Replacement {
+	return r.replacement
+}
+
+// Line returns the line of the pattern most recently returned by Next.
+func (r *Reader) Line() int {
+	return r.src.line
+}
+
+// Hyphenmins returns the values of the LEFTHYPHENMIN and RIGHTHYPHENMIN
+// directives, or 0 if they are missing. Directives may appear anywhere in the
+// dictionary, so Hyphenmins should be called after Next has returned io.EOF.
+func (r *Reader) Hyphenmins() (left, right int) {
+	return r.src.leftMin, r.src.rightMin
+}
+
+// CompoundHyphenmins returns the values of the COMPOUNDLEFTHYPHENMIN and
+// COMPOUNDRIGHTHYPHENMIN directives, or 0 if they are missing. Like
+// Hyphenmins, it should be called after Next has returned io.EOF.
+func (r *Reader) CompoundHyphenmins() (left, right int) {
+	return r.src.compoundLeftMin, r.src.compoundRightMin
+}
+
+// NoHyphen returns the character sequences of the NOHYPHEN directives, next
+// to which no breaks are allowed. Like Hyphenmins, it should be called after
+// Next has returned io.EOF.
+func (r *Reader) NoHyphen() []string {
+	return r.src.noHyphen
+}
+
+// NextLevel returns the reader for the second level of a two-level
+// dictionary, or nil if Next has not encountered a NEXTLEVEL directive.
+func (r *Reader) NextLevel() hyphenate.PatternReader {
+	if r.next == nil {
+		return nil
+	}
+	return r.next
+}
@@ -0,0 +1,147 @@
+package hunspell
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+func TestReader(t *testing.T) {
+	src := "ISO8859-1\n% comment\nLEFTHYPHENMIN 1\nRIGHTHYPHENMIN 3\n\nf\xfc1r\nc1k/k=k,1,2\n"
+	r, err := NewReader(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Charset() != "ISO8859-1" {
+		t.Fatalf("charset should be ISO8859-1, is %q", r.Charset())
+	}
+	seq, weights, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if string(seq) != "für" || !reflect.DeepEqual(weights, []int{0, 0, 1}) {
+		t.Fatalf("first pattern should be für [0 0 1], is %s %v", string(seq), weights)
+	}
+	if r.Replacement() != nil {
+		t.Fatalf("standard pattern should have no replacement")
+	}
+	if _, _, err = r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if rep := r.Replacement(); rep == nil || rep.Pre != "k" || rep.Post != "k" {
+		t.Fatalf("replacement should be k=k, is %+v", rep)
+	}
+	if _, _, err = r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if left, right := r.Hyphenmins(); left != 1 || right != 3 {
+		t.Fatalf("hyphenmins should be 1/3, are %d/%d", left, right)
+	}
+	if r.NextLevel() != nil {
+		t.Fatalf("single-level dictionary should have no next level")
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	for _, src := range []string{"", "EBCDIC\na1b\n"} {
+		if _, err := NewReader(strings.NewReader(src)); err == nil {
+			t.Errorf("source %q should be rejected", src)
+		}
+	}
+	r, err := NewReader(strings.NewReader("UTF-8\nLEFTHYPHENMIN two\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("bad directive should be reported for line 2, error is %v", err)
+	}
+	r, err = NewReader(strings.NewReader("UTF-8\na1b\nc1d e1f\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Next()
+	if _, _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("second pattern on a line should be reported for line 3, error is %v", err)
+	}
+}
+
+func TestNoHyphen(t *testing.T) {
+	src := "UTF-8\nLEFTHYPHENMIN 1\nRIGHTHYPHENMIN 1\nNOHYPHEN ',-\n1b\n1'\n'1\n"
+	dict, err := LoadPatterns("nohyphen", strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := dict.Options
+	if opts.LeftMin != 1 || opts.RightMin != 1 {
+		t.Fatalf("hyphenmins should be 1/1, are %d/%d", opts.LeftMin, opts.RightMin)
+	}
+	if h := dict.HyphenationString("abab"); h != "a-ba-b" {
+		t.Errorf("abab should be a-ba-b, is %s", h)
+	}
+	if h := dict.HyphenationString("a'bab"); h != "a'ba-b" {
+		t.Errorf("a'bab should be a'ba-b, is %s", h)
+	}
+	// Levels does not apply NOHYPHEN
+	if levels := dict.Levels("a'bab"); !reflect.DeepEqual(levels, []int{0, 0, 1, 0, 1, 0}) {
+		t.Errorf("levels of a'bab should be [0 0 1 0 1 0], are %v", levels)
+	}
+}
+
+// twoLevel finds compound boundaries after "haus" on the first level, and
+// hyphenates after every 'u' on the second.
+const twoLevel = `UTF-8
+LEFTHYPHENMIN 1
+RIGHTHYPHENMIN 2
+COMPOUNDLEFTHYPHENMIN 2
+COMPOUNDRIGHTHYPHENMIN 2
+haus1
+NEXTLEVEL
+u1
+`
+
+func TestNextLevel(t *testing.T) {
+	dict, err := LoadPatterns("compound", strings.NewReader(twoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, r := dict.Options.CompoundLeftMin, dict.Options.CompoundRightMin; l != 2 || r != 2 {
+		t.Fatalf("compound hyphenmins should be 2/2, are %d/%d", l, r)
+	}
+	tests := map[string]string{
+		"haustuer":   "haus-tu-er", // "hau-s" is too close to the boundary
+		"hausuhr":    "haus-uhr",   // "u-hr" as well
+		"bautuer":    "bau-tu-er",  // no compound
+		"haushaus":   "haus-haus",
+		"haustuhaus": "haus-tu-haus",
+	}
+	for word, want := range tests {
+		if h := dict.HyphenationString(word); h != want {
+			t.Errorf("%s should be %s, is %s", word, want, h)
+		}
+	}
+	opts := dict.Options
+	opts.CompoundLeftMin, opts.CompoundRightMin = 0, 0
+	if h := strings.Join(dict.HyphenateWith("haustuer", opts), "-"); h != "hau-s-tu-er" {
+		t.Errorf("without compound hyphenmins haustuer should be hau-s-tu-er, is %s", h)
+	}
+	if levels := dict.Levels("haustuer"); !reflect.DeepEqual(levels, []int{0, 0, 0, 1, 1, 0, 1, 0, 0}) {
+		t.Errorf("levels of haustuer are %v", levels)
+	}
+	var buf bytes.Buffer
+	if _, err := dict.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := hyphenate.ReadDictionary(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, want := range tests {
+		if h := loaded.HyphenationString(word); h != want {
+			t.Errorf("compiled: %s should be %s, is %s", word, want, h)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package hyphenate
 
 import (
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -19,6 +21,13 @@ func (r *slicePatternReader) Next() ([]rune, []int, error) {
 	return entry.Sequence, entry.Weights, nil
 }
 
+func (r *slicePatternReader) Replacement() *Replacement {
+	if r.index == 0 || r.index > len(r.entries) {
+		return nil
+	}
+	return r.entries[r.index-1].Replacement
+}
+
 type sliceExceptionReader struct {
 	entries []struct {
 		word      string
@@ -66,6 +75,26 @@ func TestPatternListAPI(t *testing.T) {
 	}
 }
 
+func TestPatternTooLong(t *testing.T) {
+	long := []rune(strings.Repeat("a", maxPatternLen) + "x")
+	dict, err := LoadPatterns("long-patterns", &slicePatternReader{
+		entries: []Pattern{
+			{Sequence: long, Weights: []int{0, 1}},
+			{Sequence: long[:maxPatternLen], Weights: []int{0, 1}},
+			{Sequence: []rune("b"), Weights: []int{1}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("pattern of %d runes should be skipped, error is %v", len(long), err)
+	}
+	if dict.patterns.EncodeRune('x') != 0 {
+		t.Errorf("runes of a skipped pattern should not be added to the alphabet")
+	}
+	if h := dict.HyphenationString("ababab"); h != "aba-bab" {
+		t.Errorf("ababab should be aba-bab, is %s", h)
+	}
+}
+
 func TestExceptionReaderAPI(t *testing.T) {
 	dict, err := LoadPatterns("stream-exceptions", &slicePatternReader{})
 	if err != nil {
@@ -87,6 +116,70 @@ func TestExceptionReaderAPI(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	dict, err := LoadPatterns("clone", &slicePatternReader{
+		entries: []Pattern{{Sequence: []rune("für"), Weights: []int{0, 0, 1}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.AddException("table", []int{0, 0, 1, 0, 0})
+	clone := dict.Clone()
+	clone.AddException("fürung", []int{0, 0, 0, 0, 0, 0})
+	clone.AddException("cable", []int{0, 0, 1, 0, 0})
+	if h := dict.HyphenationString("fürung"); h != "fü-rung" {
+		t.Errorf("original: fürung should be fü-rung, is %s", h)
+	}
+	if h := dict.HyphenationString("cable"); h != "cable" {
+		t.Errorf("original: cable should be cable, is %s", h)
+	}
+	if h := clone.HyphenationString("fürung") + " " + clone.HyphenationString("table"); h != "fürung ta-ble" {
+		t.Errorf("clone: fürung and table should be fürung ta-ble, are %s", h)
+	}
+}
+
+// replacingExceptionReader yields "Zucker" => "Zuk-ker".
+type replacingExceptionReader struct {
+	done bool
+}
+
+func (r *replacingExceptionReader) Next() (string, []int, error) {
+	if r.done {
+		return "", nil, io.EOF
+	}
+	r.done = true
+	return "Zucker", []int{0, 0, 1, 0, 0, 0}, nil
+}
+
+func (r *replacingExceptionReader) Replacements() map[int]*Replacement {
+	return map[int]*Replacement{2: {Pre: "k", Start: 2, Cut: 1}}
+}
+
+func TestNonStandardExceptions(t *testing.T) {
+	dict, err := LoadPatterns("non-standard-exceptions", &slicePatternReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dict.LoadExceptions(&replacingExceptionReader{}); err != nil {
+		t.Fatal(err)
+	}
+	if h := dict.HyphenationString("ZUCKER"); h != "ZUK-KER" {
+		t.Fatalf("ZUCKER should be ZUK-KER, is %s", h)
+	}
+	want := []Break{{Rune: 2, Byte: 2, Level: 1, From: 2, To: 3, Pre: "k"}}
+	if breaks := dict.NewHyphenator().AppendBreaks(nil, []byte("zucker")); !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("breaks of zucker should be %v, are %v", want, breaks)
+	}
+	dict.LoadExceptionList(map[string][]int{"zucker": {0, 0, 0, 1, 0, 0}})
+	if h := dict.HyphenationString("Zucker"); h != "Zuc-ker" {
+		t.Fatalf("as a standard exception Zucker should be Zuc-ker, is %s", h)
+	}
+	want = []Break{{Rune: 3, Byte: 3, Level: 1}}
+	if breaks := dict.NewHyphenator().AppendBreaks(nil, []byte("zucker")); !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("breaks of zucker should be %v, are %v", want, breaks)
+	}
+}
+
 func TestPatternTrieStats(t *testing.T) {
 	dict, err := LoadPatterns("stats", &slicePatternReader{
 		entries: []Pattern{
@@ -111,3 +204,184 @@ func TestPatternTrieStats(t *testing.T) {
 		t.Fatalf("expected fill ratio in (0,1], got %f", fill)
 	}
 }
+
+type hyphenminsPatternReader struct {
+	slicePatternReader
+	left, right int
+}
+
+func (r *hyphenminsPatternReader) Hyphenmins() (int, int) {
+	return r.left, r.right
+}
+
+func TestHyphenminsOptions(t *testing.T) {
+	patterns := []Pattern{
+		{Sequence: []rune("b"), Weights: []int{1}}, // break before every 'b'
+	}
+	dict, err := LoadPatterns("defaults", &slicePatternReader{entries: patterns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Options.LeftMin != DefaultLeftMin || dict.Options.RightMin != DefaultRightMin {
+		t.Fatalf("expected default hyphenmins, got %+v", dict.Options)
+	}
+	if h := dict.HyphenationString("babababa"); h != "ba-ba-ba-ba" {
+		t.Fatalf("babababa should be ba-ba-ba-ba, is %s", h)
+	}
+	dict, err = LoadPatterns("from-reader", &hyphenminsPatternReader{
+		slicePatternReader: slicePatternReader{entries: patterns},
+		left:               3,
+		right:              3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := dict.HyphenationString("babababa"); h != "baba-baba" {
+		t.Fatalf("with hyphenmins 3/3 babababa should be baba-baba, is %s", h)
+	}
+	dict, err = LoadPatterns("left-only", &hyphenminsPatternReader{
+		slicePatternReader: slicePatternReader{entries: patterns},
+		left:               1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Options.LeftMin != 1 || dict.Options.RightMin != DefaultRightMin {
+		t.Fatalf("expected hyphenmins 1/%d, got %+v", DefaultRightMin, dict.Options)
+	}
+	segments := dict.HyphenateWith("bbbb", Options{LeftMin: 1, RightMin: 1})
+	if len(segments) != 4 {
+		t.Fatalf("with hyphenmins 1/1 expected 4 segments, got %v", segments)
+	}
+}
+
+func TestBreaksAndLevels(t *testing.T) {
+	dict, err := LoadPatterns("breaks", &slicePatternReader{
+		entries: []Pattern{
+			{Sequence: []rune("für"), Weights: []int{0, 0, 1}},
+			{Sequence: []rune("rung"), Weights: []int{2, 0, 0, 0}},
+			{Sequence: []rune("ru"), Weights: []int{3}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	levels := dict.Levels("fürung")
+	if !reflect.DeepEqual(levels, []int{0, 0, 3, 0, 0, 0, 0}) {
+		t.Fatalf("unexpected levels for fürung: %v", levels)
+	}
+	breaks := dict.Breaks("fürung")
+	want := []Break{{Rune: 2, Byte: 3, Level: 3}}
+	if !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("unexpected breaks for fürung: %v, want %v", breaks, want)
+	}
+	dict.AddException("führung", []int{0, 0, 0, 1, 0, 0, 0})
+	breaks = dict.Breaks("führung")
+	want = []Break{{Rune: 3, Byte: 4, Level: 1}}
+	if !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("unexpected breaks for exception führung: %v, want %v", breaks, want)
+	}
+	var none *Dictionary
+	if levels := none.Levels("fürung"); !reflect.DeepEqual(levels, make([]int, 7)) {
+		t.Fatalf("a nil dictionary should yield zero levels, is %v", levels)
+	}
+}
+
+func TestCaseInsensitiveExceptions(t *testing.T) {
+	dict, err := LoadPatterns("casing", &slicePatternReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.AddException("Table", []int{0, 0, 1, 0, 0})
+	for word, want := range map[string]string{
+		"table": "ta-ble",
+		"Table": "Ta-ble",
+		"TABLE": "TA-BLE",
+	} {
+		if h := dict.HyphenationString(word); h != want {
+			t.Fatalf("%s should be %s, is %s", word, want, h)
+		}
+	}
+}
+
+func TestSupplementaryPlanePatterns(t *testing.T) {
+	dict, err := LoadPatterns("supplementary", &slicePatternReader{
+		entries: []Pattern{
+			{Sequence: []rune("𐌰𐌱"), Weights: []int{0, 1}}, // Gothic
+			{Sequence: []rune("𐐨𐐩"), Weights: []int{0, 1}}, // Deseret, lowercase
+			{Sequence: []rune("𝐚𝐛"), Weights: []int{0, 1}}, // mathematical bold
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "𐌰𐌰𐌰𐌱𐌰𐌰", want: "𐌰𐌰𐌰-𐌱𐌰𐌰"},
+		{word: "𐐀𐐀𐐨𐐩𐐨𐐨", want: "𐐀𐐀𐐨-𐐩𐐨𐐨"},
+		{word: "𐐀𐐀𐐀𐐁𐐀𐐀", want: "𐐀𐐀𐐀-𐐁𐐀𐐀"}, // Deseret, uppercase
+		{word: "𝐚𝐚𝐚𝐛𝐚𝐚", want: "𝐚𝐚𝐚-𝐛𝐚𝐚"},
+	}
+	for _, tt := range tests {
+		if h := dict.HyphenationString(tt.word); h != tt.want {
+			t.Fatalf("%s should be %s, is %s", tt.word, tt.want, h)
+		}
+	}
+	if breaks := dict.Breaks("𐌰𐌰𐌰𐌱𐌰𐌰"); len(breaks) != 1 || breaks[0].Rune != 3 || breaks[0].Byte != 12 {
+		t.Fatalf("unexpected breaks %v", breaks)
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	patterns := []string{"a1bcd", "b2c", "c1d", "1bcx", ".a4b", "d3a.", "1ab", "abx1"}
+	var entries []Pattern
+	for _, s := range patterns {
+		p, err := ParsePattern(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, p)
+	}
+	dict, err := LoadPatterns("matchers", &slicePatternReader{entries: entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dict.patterns.HasFailureLinks() {
+		t.Fatalf("expected failure links after Freeze")
+	}
+	for _, word := range []string{"abcd", "abcda", "aabcxabcd", "xbcdabx", "dada", "q", ""} {
+		wordRunes := []rune(word)
+		dict.Options.Matcher = AhoCorasick
+		ac := dict.patternLevels(wordRunes)
+		dict.Options.Matcher = SuffixWalk
+		walk := dict.patternLevels(wordRunes)
+		if !reflect.DeepEqual(ac, walk) {
+			t.Errorf("levels of %q differ: Aho-Corasick %v, suffix walk %v", word, ac, walk)
+		}
+	}
+	dict.Options.Matcher = AhoCorasick
+	if levels := dict.patternLevels([]rune("abcda")); !reflect.DeepEqual(levels, []int{1, 4, 2, 1, 3, 0}) {
+		t.Errorf("unexpected levels for abcda: %v", levels)
+	}
+}
+
+func TestParseException(t *testing.T) {
+	tests := []struct {
+		text      string
+		word      string
+		positions []int
+	}{
+		{"ta-ble", "table", []int{0, 0, 1, 0, 0}},
+		{"-ab", "ab", []int{1, 0}},
+		{"ab-", "ab", []int{0, 0, 1}},
+		{"a--b", "ab", []int{0, 1, 1}},
+	}
+	for _, tt := range tests {
+		word, positions := ParseException(tt.text)
+		if word != tt.word || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("%s should be %s %v, is %s %v", tt.text, tt.word, tt.positions, word, positions)
+		}
+	}
+}
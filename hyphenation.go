@@ -3,7 +3,10 @@ package hyphenate
 import (
 	"fmt"
 	"io"
+	"maps"
 	"strings"
+	"sync"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -12,9 +15,11 @@ import (
 // Sequence is the rune sequence to match (for example: ".ab", "für").
 // Weights stores Liang weights by relative position and may be longer than
 // Sequence by one entry when a pattern has a trailing weight digit.
+// Replacement is set for non-standard patterns only.
 type Pattern struct {
-	Sequence []rune
-	Weights  []int
+	Sequence    []rune
+	Weights     []int
+	Replacement *Replacement
 }
 
 // PatternReader yields compiled pattern entries one-by-one.
@@ -29,16 +34,126 @@ type ExceptionReader interface {
 	Next() (word string, positions []int, err error)
 }
 
+// ExceptionReplacementReader may be implemented by an ExceptionReader which
+// yields non-standard exceptions, e.g. "Zucker" => "Zuk-ker".
+// LoadExceptions calls Replacements after every exception returned by Next.
+// It maps positions of breaks to their replacements, where Start and Cut
+// refer to the runes of the word in Unicode normalization form NFC; it
+// returns nil for standard exceptions.
+type ExceptionReplacementReader interface {
+	Replacements() map[int]*Replacement
+}
+
+// ReplacementReader may be implemented by a PatternReader which yields
+// non-standard patterns. LoadPatterns calls Replacement after every pattern
+// returned by Next; it returns nil for standard patterns.
+type ReplacementReader interface {
+	Replacement() *Replacement
+}
+
+// LineReader may be implemented by a PatternReader which knows the source
+// lines of its patterns. LoadPatterns calls Line after every pattern returned
+// by Next; it returns 0 if the line is unknown. The lines are reported by
+// Dictionary.Explain, and kept in compiled dictionaries on request (see
+// CompileOptions).
+type LineReader interface {
+	Line() int
+}
+
+// HyphenminsReader may be implemented by a PatternReader which knows about the
+// hyphenmins of its source, i.e. the minimum number of characters before the
+// first and after the last hyphen of a word. Values <= 0 mean "unknown", and
+// the default applies to them, independently of the other value.
+// LoadPatterns queries it after the pattern stream is exhausted.
+type HyphenminsReader interface {
+	Hyphenmins() (left, right int)
+}
+
+// Default hyphenmins, used if a pattern source does not specify its own.
+const (
+	DefaultLeftMin  = 2
+	DefaultRightMin = 2
+)
+
+// Matcher selects the algorithm which matches patterns against a word. Both
+// algorithms yield the same results.
+type Matcher uint8
+
+const (
+	// AhoCorasick matches a word in a single linear pass over the trie,
+	// following failure links. This is the default.
+	AhoCorasick Matcher = iota
+	// SuffixWalk restarts a trie walk at every suffix of a word. Its cost is
+	// quadratic in the length of the word, but it does not need failure links.
+	SuffixWalk
+)
+
+// Options control the hyphenation of words.
+//
+// Every dictionary carries default options in Dictionary.Options. They may be
+// overridden for single calls, e.g. with HyphenateWith.
+type Options struct {
+	LeftMin     int     // minimum number of runes before the first hyphen
+	RightMin    int     // minimum number of runes after the last hyphen
+	SkipAllCaps bool    // do not hyphenate words in capitals only (cf. TeX's \uchyph)
+	Matcher     Matcher // pattern matching algorithm, Aho-Corasick by default
+
+	// Hyphenmins within the parts of compound words, for two-level
+	// dictionaries only (see CompoundReader): the minimum number of runes
+	// between a compound boundary and a hyphen after or before it
+	CompoundLeftMin  int
+	CompoundRightMin int
+
+	// Options for discretionaries (see DiscretionariesWith)
+	HyphenChar       rune // hyphen inserted at breaks; 0 means '-'
+	HyphenPenalty    int  // penalty for breaks at hyphenation opportunities
+	ExHyphenPenalty  int  // penalty for breaks at hard hyphens
+	RepeatHardHyphen bool // repeat hard hyphens on the next line (Polish, Portuguese); never set by loaders
+}
+
 // Dictionary is a loaded hyphenation dictionary.
 //
 // A dictionary contains:
 //   - pattern rules (compiled into a pattern trie backend + compact metadata store)
 //   - explicit hyphenation exceptions loaded through ExceptionReader.
 type Dictionary struct {
-	exceptions map[string][]int // e.g., "computer" => [3,5] = "com-pu-ter"
+	exceptions map[string][]int                // e.g., "computer" => [3,5] = "com-pu-ter"
+	exceptReps map[string]map[int]*Replacement // of non-standard exceptions, by position
 	patterns   patternTrie
 	patternsV  *patternStore // compact metadata vectors by pattern id
 	Identifier string        // Identifies the dictionary
+	Options    Options       // Default options, initialized from the pattern source
+	Metadata   *Metadata     // Metadata of the pattern source, nil if unknown
+	pool       sync.Pool     // of *Hyphenator, for the convenience API
+	mapped     []byte        // memory-mapped compiled dictionary, if any
+	letters    map[rune]rune // declared letters => lowercase form
+	noHyphen   [][]rune      // sequences without breaks before and after them
+	nextLevel  *Dictionary   // patterns for the parts of compound words, if any
+	source     string        // name of the pattern source, see Explain
+}
+
+// Clone returns a copy of the dictionary, whose exceptions and options may be
+// changed without affecting dict, e.g. for a dictionary shared through a
+// Registry. The patterns are shared: a memory-mapped dict must not be closed
+// while the copy is in use.
+func (dict *Dictionary) Clone() *Dictionary {
+	clone := &Dictionary{
+		exceptions: maps.Clone(dict.exceptions),
+		exceptReps: maps.Clone(dict.exceptReps),
+		patterns:   dict.patterns,
+		patternsV:  dict.patternsV,
+		Identifier: dict.Identifier,
+		Options:    dict.Options,
+		Metadata:   dict.Metadata,
+		letters:    dict.letters,
+		noHyphen:   dict.noHyphen,
+		nextLevel:  dict.nextLevel,
+		source:     dict.source,
+	}
+	if clone.exceptions == nil {
+		clone.exceptions = make(map[string][]int)
+	}
+	return clone
 }
 
 // PatternTrieStats reports density metrics for the underlying pattern trie.
@@ -50,7 +165,12 @@ func (dict *Dictionary) PatternTrieStats() (backend string, usedSlots, totalSlot
 	return stats.Backend, stats.UsedSlots, stats.TotalSlots, stats.MaxStateID, stats.FillRatio()
 }
 
+// maxPatternLen is the maximum length of a pattern in runes, including dots,
+// as the depth of trie states is stored in a byte (see dat.DAT.Depth).
+const maxPatternLen = 255
+
 // LoadPatterns compiles patterns from a streaming, format-agnostic source.
+// Patterns longer than 255 runes are skipped.
 //
 // File format parsing is intentionally outside the base package. Use adapters
 // like package texpatterns to parse concrete formats and feed this API.
@@ -59,13 +179,22 @@ func LoadPatterns(name string, reader PatternReader) (dict *Dictionary, err erro
 	type pendingPayload struct {
 		pos    int
 		packed []byte
+		rep    *Replacement
+		line   int
 	}
+	replReader, _ := reader.(ReplacementReader)
+	lineReader, _ := reader.(LineReader)
 	pending := make([]pendingPayload, 0, 1024)
 	maxPacked := 0
 	dict = &Dictionary{
 		exceptions: make(map[string][]int),
 		patterns:   trie,
 		Identifier: fmt.Sprintf("patterns: %s", name),
+		source:     name,
+		Options: Options{
+			HyphenPenalty:   DefaultHyphenPenalty,
+			ExHyphenPenalty: DefaultExHyphenPenalty,
+		},
 	}
 	var sequence []rune
 	var weights []int
@@ -77,6 +206,10 @@ func LoadPatterns(name string, reader PatternReader) (dict *Dictionary, err erro
 		if err != nil {
 			return
 		}
+		if len(sequence) > maxPatternLen {
+			tracer().Errorf("skipping pattern %q, which exceeds %d runes", string(sequence), maxPatternLen)
+			continue
+		}
 		key, ok := dict.patterns.EncodeKey(string(sequence))
 		if !ok {
 			continue // simply skip invalid patterns
@@ -94,9 +227,50 @@ func LoadPatterns(name string, reader PatternReader) (dict *Dictionary, err erro
 		if len(packed) > maxPacked {
 			maxPacked = len(packed)
 		}
-		pending = append(pending, pendingPayload{pos: pos, packed: packed})
+		var rep *Replacement
+		if replReader != nil {
+			if r := replReader.Replacement(); r != nil {
+				rep = &Replacement{}
+				*rep = *r
+			}
+		}
+		line := 0
+		if lineReader != nil {
+			line = lineReader.Line()
+		}
+		pending = append(pending, pendingPayload{pos: pos, packed: packed, rep: rep, line: line})
+	}
+	if cr, ok := reader.(CompoundReader); ok {
+		if next := cr.NextLevel(); next != nil {
+			if dict.nextLevel, err = LoadPatterns(name, next); err != nil {
+				return
+			}
+		}
+		l, r := cr.CompoundHyphenmins()
+		dict.Options.CompoundLeftMin, dict.Options.CompoundRightMin = max(l, 0), max(r, 0)
+	}
+	left, right := DefaultLeftMin, DefaultRightMin
+	if hr, ok := reader.(HyphenminsReader); ok {
+		l, r := hr.Hyphenmins()
+		if l > 0 {
+			left = l
+		}
+		if r > 0 {
+			right = r
+		}
+	}
+	dict.patterns.SetHyphenmins(left, right)
+	if mr, ok := reader.(MetadataReader); ok {
+		dict.Metadata = mr.Metadata()
+	}
+	if lr, ok := reader.(LetterReader); ok {
+		dict.setLetters(lr.Letters())
+	}
+	if nr, ok := reader.(NoHyphenReader); ok {
+		dict.setNoHyphen(nr.NoHyphen())
 	}
 	dict.patterns.Freeze()
+	dict.Options.LeftMin, dict.Options.RightMin = dict.patterns.Hyphenmins()
 	dict.patternsV = newPatternStore(uint8(maxPacked))
 	for _, p := range pending {
 		patternID := dict.patterns.ResolvePosition(p.pos)
@@ -107,6 +281,12 @@ func LoadPatterns(name string, reader PatternReader) (dict *Dictionary, err erro
 		if err = dict.patternsV.PutPacked(patternID, p.packed); err != nil {
 			return
 		}
+		if p.rep != nil {
+			dict.patternsV.PutReplacement(patternID, p.rep)
+		}
+		if p.line > 0 {
+			dict.patternsV.PutLine(patternID, p.line)
+		}
 	}
 	backend, used, total, maxStateID, fill := dict.PatternTrieStats()
 	tracer().Infof("pattern trie stats backend=%s used=%d total=%d fill=%.2f maxStateID=%d",
@@ -116,6 +296,7 @@ func LoadPatterns(name string, reader PatternReader) (dict *Dictionary, err erro
 
 // LoadExceptions loads exception entries from a streaming source.
 func (dict *Dictionary) LoadExceptions(reader ExceptionReader) (err error) {
+	repReader, _ := reader.(ExceptionReplacementReader)
 	for {
 		var word string
 		var positions []int
@@ -126,10 +307,32 @@ func (dict *Dictionary) LoadExceptions(reader ExceptionReader) (err error) {
 			break
 		}
 		dict.AddException(word, positions)
+		if repReader != nil {
+			dict.setExceptionReplacements(word, repReader.Replacements())
+		}
 	}
 	return err
 }
 
+// setExceptionReplacements stores the replacements of a non-standard
+// exception, see ExceptionReplacementReader.
+func (dict *Dictionary) setExceptionReplacements(word string, reps map[int]*Replacement) {
+	lower, _ := dict.foldLetters(normalizeWord(word).nfc)
+	if len(reps) == 0 {
+		delete(dict.exceptReps, lower)
+		return
+	}
+	if dict.exceptReps == nil {
+		dict.exceptReps = make(map[string]map[int]*Replacement)
+	}
+	stored := make(map[int]*Replacement, len(reps))
+	for pos, rep := range reps {
+		r := *rep
+		stored[pos] = &r
+	}
+	dict.exceptReps[lower] = stored
+}
+
 // LoadExceptionList loads explicit exception entries from an in-memory map.
 func (dict *Dictionary) LoadExceptionList(exceptions map[string][]int) {
 	for word, positions := range exceptions {
@@ -138,106 +341,287 @@ func (dict *Dictionary) LoadExceptionList(exceptions map[string][]int) {
 }
 
 // AddException registers one explicit hyphenation exception.
+// Exceptions are case-insensitive, i.e. word is stored in lowercase, and
+// stored in Unicode normalization form NFC. A standard exception replaces a
+// non-standard exception for the same word.
 func (dict *Dictionary) AddException(word string, positions []int) {
 	if dict.exceptions == nil {
 		dict.exceptions = make(map[string][]int)
 	}
-	pp := make([]int, len(positions))
-	copy(pp, positions)
-	dict.exceptions[word] = pp
+	w := normalizeWord(word)
+	var pp []int
+	if w.offsets == nil {
+		pp = make([]int, len(positions))
+		copy(pp, positions)
+	} else { // positions refer to the runes of the unnormalized word
+		pp = make([]int, len(w.offsets)-1)
+		for k := range pp {
+			if j := w.origRune(k); w.offsets[k] >= 0 && j < len(positions) {
+				pp[k] = positions[j]
+			}
+		}
+	}
+	lower, _ := dict.foldLetters(w.nfc)
+	dict.exceptions[lower] = pp
+	delete(dict.exceptReps, lower)
+}
+
+// ParseException parses an exception with breaks marked by hyphens, as in
+// TeX's \hyphenation and hyph-utf8's .hyp.txt files, into the word without
+// hyphens and the positions for AddException, e.g.
+//
+//	"ta-ble" => "table", [0,0,1,0,0].
+//
+// positions[i] is 1 if a hyphen precedes rune i of word. Readers of exception
+// formats use it, so that the formats agree on edge cases like "--" or
+// leading and trailing hyphens.
+func ParseException(s string) (word string, positions []int) {
+	positions = make([]int, 0, len(s))
+	wasHyphen := false
+	for _, ch := range s {
+		if ch == '-' {
+			positions = append(positions, 1)
+			wasHyphen = true
+		} else if wasHyphen {
+			wasHyphen = false
+		} else {
+			positions = append(positions, 0)
+		}
+	}
+	return strings.ReplaceAll(s, "-", ""), positions
 }
 
 // HyphenationString returns word with discretionary hyphens inserted.
 // Example:
 //
 //	"table" => "ta-ble".
+//
+// HyphenationString is a shortcut for dict.Encode(word, Hyphen).
 func (dict *Dictionary) HyphenationString(word string) string {
-	s := dict.Hyphenate(word)
-	return strings.Join(s, "-")
+	return dict.Encode(word, Hyphen)
 }
 
-// Hyphenate splits word at legal hyphenation positions.
+// Hyphenate splits word at legal hyphenation positions, using the
+// dictionary's default options.
 //
 // Example:
 //
 //	"table" => [ "ta", "ble" ].
 func (dict *Dictionary) Hyphenate(word string) []string {
-	const (
-		leftMin  = 2
-		rightMin = 2
-	)
-	if dict == nil || dict.patterns == nil || dict.patternsV == nil {
+	if dict == nil {
 		return []string{word}
 	}
-	if positions, found := dict.exceptions[word]; found {
-		return splitAtPositions(word, positions)
-	}
-	wordRunes := []rune(word)
-	dottedword := make([]rune, 0, len(wordRunes)+2)
-	dottedword = append(dottedword, '.')
-	dottedword = append(dottedword, wordRunes...)
-	dottedword = append(dottedword, '.')
-	positions := make([]int, len(dottedword)) // the resulting hyphenation positions
-	for i := range len(dottedword) {          // "word", "ord", "rd", "d"
-		positions = mergePrefixPositions(string(dottedword[i:]), dict.patterns, dict.patternsV, i, positions)
-	}
-	positions = positions[1 : len(positions)-1]
-	for i := 0; i < leftMin && i < len(positions); i++ {
-		positions[i] = 0 // disallow breaks too close to the left edge
-	}
-	rightCutoff := len(wordRunes) - rightMin + 1 // indices >= cutoff leave fewer than rightMin chars
-	rightCutoff = max(0, rightCutoff)
-	for i := rightCutoff; i < len(positions); i++ {
-		positions[i] = 0 // disallow breaks too close to the right edge
-	}
-	return splitAtPositions(word, positions)
-}
-
-// mergePrefixPositions looks up all prefixes of a fragment and merges matching
-// pattern weights into positions at absolute offset at.
-func mergePrefixPositions(wordfragment string, tr patternTrie, store *patternStore, at int,
-	positions []int) []int {
-	//
-	key, ok := tr.EncodeKey(wordfragment)
-	if !ok {
-		return positions
-	}
-	it := tr.Iterator()
-	for _, c := range key {
-		patternID := it.Next(c)
-		if patternID == 0 {
-			break
+	return dict.HyphenateWith(word, dict.Options)
+}
+
+// HyphenateWith splits word at legal hyphenation positions, using options opts
+// instead of the dictionary's default options.
+//
+// Explicit exceptions are taken verbatim and are not subject to the
+// hyphenmins of opts.
+func (dict *Dictionary) HyphenateWith(word string, opts Options) []string {
+	return splitAtBreaks(word, dict.BreaksWith(word, opts))
+}
+
+// Break is a hyphenation opportunity within a word.
+//
+// Breaks produced by non-standard patterns (see Replacement) additionally
+// carry the text change applied if the break is taken: bytes From to To of
+// the word are replaced by Pre, followed by the hyphen and the line break,
+// followed by Post. For standard breaks these fields are all zero.
+type Break struct {
+	Rune  int // rune offset of the break; the break is located before this rune
+	Byte  int // byte offset of the break; the break is located before this byte
+	Level int // odd Liang level producing the break (exceptions report 1)
+
+	From, To  int    // byte range of the word replaced for non-standard breaks
+	Pre, Post string // replacement text for non-standard breaks
+}
+
+// IsNonStandard reports whether taking the break changes the text of the word.
+func (brk Break) IsNonStandard() bool {
+	return brk.From != brk.To || brk.Pre != "" || brk.Post != ""
+}
+
+// span returns the byte range of the word replaced when the break is taken.
+// It is empty for standard breaks.
+func (brk Break) span() (from, to int) {
+	if brk.IsNonStandard() {
+		return brk.From, brk.To
+	}
+	return brk.Byte, brk.Byte
+}
+
+// Breaks returns the hyphenation opportunities of word, using the
+// dictionary's default options.
+//
+// Example:
+//
+//	"table" => [ {Rune:2 Byte:2 Level:1} ].
+func (dict *Dictionary) Breaks(word string) []Break {
+	if dict == nil {
+		return nil
+	}
+	return dict.BreaksWith(word, dict.Options)
+}
+
+// BreaksWith returns the hyphenation opportunities of word, using options opts
+// instead of the dictionary's default options.
+//
+// Lookup is case-insensitive and normalization-aware: word is converted to
+// Unicode normalization form NFC and folded to lowercase before matching
+// exceptions and patterns, while offsets refer to word as given. Breaks are
+// never placed between a base character and its combining marks.
+func (dict *Dictionary) BreaksWith(word string, opts Options) []Break {
+	if dict == nil {
+		return nil
+	}
+	w := normalizeWord(word)
+	h := dict.hyphenator()
+	defer dict.pool.Put(h)
+	h.Options = opts
+	breaks := h.appendBreaks(nil, []byte(w.nfc))
+	if reps := dict.exceptReps[string(h.lower)]; h.exception && reps != nil {
+		for i := range breaks {
+			applyExceptionReplacement(w, reps[breaks[i].Rune], &breaks[i])
+		}
+	} else if dict.patternsV.HasReplacements() && !h.exception {
+		for i := range breaks {
+			dict.applyReplacement(w, h.runes, &breaks[i])
 		}
-		positions = store.MergeInto(patternID, at, positions)
 	}
-	return positions
+	return w.mapBreaks(breaks)
 }
 
-// Helper: split a string at positions given by an integer slice.
-func splitAtPositions(word string, positions []int) []string {
-	offsets := runeByteOffsets(word)
-	runeCount := len(offsets) - 1
-	var pp = make([]string, 0, max(1, runeCount/3))
-	prev := 0                       // holds the last split index
-	for i, pos := range positions { // check every position
-		if i <= 0 || i >= runeCount {
-			continue
+// hyphenator returns a pooled hyphenation context.
+func (dict *Dictionary) hyphenator() *Hyphenator {
+	if h, ok := dict.pool.Get().(*Hyphenator); ok {
+		return h
+	}
+	return dict.NewHyphenator()
+}
+
+// applyReplacement looks for the non-standard pattern which produced brk, if
+// any, and sets the replacement fields of brk accordingly. The offsets of brk
+// refer to w.nfc, while From and To are set as offsets into w.orig. Following libhyphen,
+// the first pattern carrying a replacement and providing the break's level
+// wins.
+func (dict *Dictionary) applyReplacement(w normalizedWord, wordRunes []rune, brk *Break) {
+	key := appendDottedKey(make([]uint16, 0, len(wordRunes)+2), dict.patterns, wordRunes)
+	abs := brk.Rune + 1 // break position in dotted word
+	for at := 0; at <= abs && at < len(key); at++ {
+		patternID := dict.patterns.Root()
+		for _, c := range key[at:] {
+			if patternID = dict.patterns.Step(patternID, c); patternID == 0 {
+				break
+			}
+			rep := dict.patternsV.Replacement(patternID)
+			if rep == nil || dict.patternsV.LevelAt(patternID, abs-at) != brk.Level {
+				continue
+			}
+			start := min(max(at+rep.Start-1, 0), len(wordRunes)) // rune offset in word
+			end := min(start+rep.Cut, len(wordRunes))
+			brk.From, brk.To = w.origOffset(start), w.origOffset(end)
+			orig := w.orig[brk.From:brk.To]
+			brk.Pre, brk.Post = matchCase(rep.Pre, orig), matchCase(rep.Post, orig)
+			return
+		}
+	}
+}
+
+// applyExceptionReplacement sets the replacement fields of brk from the
+// replacement of a non-standard exception, if rep is not nil.
+func applyExceptionReplacement(w normalizedWord, rep *Replacement, brk *Break) {
+	if rep == nil {
+		return
+	}
+	brk.From, brk.To = w.origOffset(rep.Start), w.origOffset(rep.Start+rep.Cut)
+	orig := w.orig[brk.From:brk.To]
+	brk.Pre, brk.Post = matchCase(rep.Pre, orig), matchCase(rep.Post, orig)
+}
+
+// Levels returns the raw Liang levels for word, as computed from the patterns
+// alone, i.e. neither exceptions, hyphenmins, compound hyphenmins nor NOHYPHEN
+// sequences (see NoHyphenReader) are applied. Breaks may thus reject
+// positions with odd levels; Explain lists the rules which do. For two-level
+// dictionaries (see CompoundReader), odd levels at compound boundaries come
+// from the first level and the levels within the parts from the second.
+//
+// The result has one entry per inter-letter position, including both word
+// edges: levels[i] is the level of the position before rune i, and
+// levels[n] is the level after the last of n runes. Odd levels allow a break,
+// even levels inhibit it.
+func (dict *Dictionary) Levels(word string) []int {
+	if dict == nil || dict.patterns == nil || dict.patternsV == nil {
+		return make([]int, utf8.RuneCountInString(word)+1)
+	}
+	w := normalizeWord(word)
+	lower, _ := dict.foldLetters(w.nfc)
+	wordRunes := []rune(lower)
+	levels := dict.patternLevels(wordRunes)
+	if w.offsets == nil {
+		return levels
+	}
+	// map levels back to the positions of the unnormalized word
+	orig := make([]int, utf8.RuneCountInString(word)+1)
+	for k, level := range levels {
+		if k == len(wordRunes) {
+			orig[len(orig)-1] = level
+		} else if w.offsets[k] >= 0 {
+			orig[w.origRune(k)] = level
 		}
-		if pos > 0 && pos%2 != 0 { // if position is odd > 0
-			split := offsets[i]
-			pp = append(pp, word[prev:split]) // append syllable
-			prev = split                      // remember last split index
+	}
+	return orig
+}
+
+// patternLevels computes the Liang levels for a word, with len(wordRunes)+1
+// entries (see Levels).
+func (dict *Dictionary) patternLevels(wordRunes []rune) []int {
+	key := appendDottedKey(make([]uint16, 0, len(wordRunes)+2), dict.patterns, wordRunes)
+	levels := matchPatterns(dict.patterns, dict.patternsV, key, nil, dict.Options.Matcher)
+	// levels[k] is the level before dotted rune k, thus levels[1] is the
+	// level before the first rune of the word
+	levels = levels[1 : len(wordRunes)+2]
+	if dict.nextLevel != nil {
+		opts := Options{Matcher: dict.Options.Matcher}
+		return dict.compoundLevels(wordRunes, levels, opts, &compoundScratch{})
+	}
+	return levels
+}
+
+// foldCase maps word to lowercase, rune by rune. As every rune is mapped to
+// exactly one rune, rune offsets into the result are valid for word as well.
+// allCaps reports whether word contains upper case letters, but no lower case
+// letters.
+func foldCase(word string) (lower string, allCaps bool) {
+	hasUpper, hasLower := false, false
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		} else if unicode.IsLower(r) {
+			hasLower = true
 		}
 	}
-	pp = append(pp, word[prev:]) // append last syllable
-	return pp
+	if !hasUpper {
+		return word, false
+	}
+	return strings.Map(unicode.ToLower, word), !hasLower
 }
 
-func runeByteOffsets(s string) []int {
-	offsets := make([]int, 0, utf8.RuneCountInString(s)+1)
-	for i := range s {
-		offsets = append(offsets, i)
+// Helper: split a string at the byte offsets of breaks. Replacements of
+// non-standard breaks are applied.
+func splitAtBreaks(word string, breaks []Break) []string {
+	pp := make([]string, 0, len(breaks)+1)
+	prev := 0    // holds the last split index
+	prefix := "" // holds the post-break text of the last split
+	for _, brk := range breaks {
+		from, to := brk.span()
+		if from < prev { // overlapping replacements
+			continue
+		}
+		pp = append(pp, prefix+word[prev:from]+brk.Pre) // append syllable
+		prev, prefix = to, brk.Post                     // remember last split
 	}
-	offsets = append(offsets, len(s))
-	return offsets
+	pp = append(pp, prefix+word[prev:]) // append last syllable
+	return pp
 }
@@ -0,0 +1,187 @@
+package hyphenate
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Hyphenator is a reusable hyphenation context for high-volume batch jobs.
+//
+// A Hyphenator owns scratch buffers for the hyphenation of single words.
+// Once the buffers have grown to the length of the longest word, AppendBreaks
+// does not allocate for words in Unicode normalization form NFC. Words
+// which need normalization or match non-standard exceptions, and dictionaries
+// with non-standard patterns, take the general (allocating) path of
+// Dictionary.BreaksWith.
+//
+// A Hyphenator must not be used concurrently. Create one per goroutine.
+type Hyphenator struct {
+	Options Options // options for hyphenation, initialized from the dictionary
+
+	dict   *Dictionary
+	runes  []rune   // word folded to lowercase
+	lower  []byte   // word folded to lowercase, UTF-8 encoded
+	key    []uint16 // dense key of the dotted word
+	levels []int    // Liang levels of the dotted word
+	parts  compoundScratch
+
+	exception bool // last word has been found in the exceptions
+}
+
+// NewHyphenator creates a reusable hyphenation context for dict, using the
+// dictionary's default options.
+func (dict *Dictionary) NewHyphenator() *Hyphenator {
+	return &Hyphenator{
+		Options: dict.Options,
+		dict:    dict,
+		runes:   make([]rune, 0, 32),
+		lower:   make([]byte, 0, 64),
+		key:     make([]uint16, 0, 34),
+		levels:  make([]int, 0, 35),
+	}
+}
+
+// AppendBreaks appends the hyphenation opportunities of word to dst and
+// returns the extended slice. Offsets refer to word.
+//
+// Example:
+//
+//	h := dict.NewHyphenator()
+//	var breaks []hyphenate.Break
+//	for _, word := range words {
+//		breaks = h.AppendBreaks(breaks[:0], word)
+//		...
+//	}
+func (h *Hyphenator) AppendBreaks(dst []Break, word []byte) []Break {
+	if h.dict == nil {
+		return dst
+	}
+	if h.dict.patternsV.HasReplacements() || !norm.NFC.IsNormal(word) {
+		return append(dst, h.dict.BreaksWith(string(word), h.Options)...)
+	}
+	n := len(dst)
+	dst = h.appendBreaks(dst, word)
+	if h.exception && h.dict.exceptReps[string(h.lower)] != nil { // does not allocate
+		return append(dst[:n], h.dict.BreaksWith(string(word), h.Options)...)
+	}
+	return dst
+}
+
+// appendBreaks is the hyphenation core. word has to be in NFC. Replacements
+// of non-standard patterns are not considered.
+func (h *Hyphenator) appendBreaks(dst []Break, word []byte) []Break {
+	dict := h.dict
+	h.runes, h.lower = h.runes[:0], h.lower[:0]
+	hasUpper, hasLower := false, false
+	for i := 0; i < len(word); {
+		r, size := utf8.DecodeRune(word[i:])
+		i += size
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		} else if unicode.IsLower(r) {
+			hasLower = true
+		}
+		r = dict.toLower(r)
+		h.runes = append(h.runes, r)
+		h.lower = utf8.AppendRune(h.lower, r)
+	}
+	h.exception = false
+	if hasUpper && !hasLower && h.Options.SkipAllCaps {
+		return dst
+	}
+	if positions, found := dict.exceptions[string(h.lower)]; found { // does not allocate
+		h.exception = true
+		return appendOddPositions(dst, word, positions)
+	}
+	if dict.patterns == nil || dict.patternsV == nil {
+		return dst
+	}
+	n := len(h.runes)
+	h.key = appendDottedKey(h.key[:0], dict.patterns, h.runes)
+	h.levels = matchPatterns(dict.patterns, dict.patternsV, h.key, h.levels, h.Options.Matcher)
+	levels := h.levels[1 : n+2] // levels[i] is the level before rune i
+	if dict.nextLevel != nil {
+		levels = dict.compoundLevels(h.runes, levels, h.Options, &h.parts)
+	}
+	dict.inhibitNoHyphen(h.runes, levels)
+	for i := 0; i < h.Options.LeftMin && i <= n; i++ {
+		levels[i] = 0 // disallow breaks too close to the left edge
+	}
+	for i := max(0, n-h.Options.RightMin+1); i <= n; i++ {
+		levels[i] = 0 // disallow breaks too close to the right edge
+	}
+	return appendOddPositions(dst, word, levels)
+}
+
+// appendOddPositions appends a break for every odd position of a word, where
+// positions[i] refers to the position before rune i. Positions at the word
+// edges or before combining marks are ignored.
+func appendOddPositions(dst []Break, word []byte, positions []int) []Break {
+	for b, i := 0, 0; b < len(word); i++ {
+		r, size := utf8.DecodeRune(word[b:])
+		if i > 0 && i < len(positions) && positions[i]%2 != 0 && !unicode.IsMark(r) {
+			dst = append(dst, Break{Rune: i, Byte: b, Level: positions[i]})
+		}
+		b += size
+	}
+	return dst
+}
+
+// appendDottedKey appends the dense key of a word, enclosed in word boundary
+// markers '.', to key.
+func appendDottedKey(key []uint16, tr patternTrie, wordRunes []rune) []uint16 {
+	dot := tr.EncodeRune('.')
+	key = append(key, dot)
+	for _, r := range wordRunes {
+		key = append(key, tr.EncodeRune(r))
+	}
+	return append(key, dot)
+}
+
+// matchPatterns computes the Liang levels of a dotted key, using matcher m.
+// The weights of every pattern found are merged into levels, which is reset
+// and resized to len(key)+1 entries. levels[k] is the level before key
+// symbol k. Tries without failure links are matched by walking suffixes.
+func matchPatterns(tr patternTrie, store *patternStore, key []uint16, levels []int, m Matcher) []int {
+	levels = resetLevels(levels, len(key)+1)
+	if m == SuffixWalk || !tr.HasFailureLinks() {
+		return walkSuffixes(tr, store, key, levels)
+	}
+	state := tr.Root()
+	for j, c := range key {
+		state = tr.Next(state, c)
+		// report every pattern ending at key symbol j
+		for s := state; s != 0; s = tr.Output(s) {
+			levels = store.MergeInto(s, j+1-tr.Depth(s), levels)
+		}
+	}
+	return levels
+}
+
+// walkSuffixes walks the trie for every suffix of the key ("word", "ord",
+// "rd", "d") and merges the weights of every pattern found into levels.
+func walkSuffixes(tr patternTrie, store *patternStore, key []uint16, levels []int) []int {
+	root := tr.Root()
+	for i := range key {
+		state := root
+		for _, c := range key[i:] {
+			if state = tr.Step(state, c); state == 0 {
+				break
+			}
+			levels = store.MergeInto(state, i, levels)
+		}
+	}
+	return levels
+}
+
+// resetLevels returns levels with n zero entries, re-using its storage.
+func resetLevels(levels []int, n int) []int {
+	if cap(levels) < n {
+		return make([]int, n)
+	}
+	levels = levels[:n]
+	clear(levels)
+	return levels
+}
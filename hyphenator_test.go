@@ -0,0 +1,158 @@
+package hyphenate_test
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+var benchWords = []string{
+	"Silbentrennung", "Donaudampfschifffahrtsgesellschaft", "Zuckerbäcker",
+	"Hyphenation", "Rechtsschutzversicherungsgesellschaften", "Straße",
+	"Bundesverfassungsgericht", "Eisenbahn", "Häuser", "Programmiersprache",
+}
+
+var german struct {
+	once sync.Once
+	dict *hyphenate.Dictionary
+	err  error
+}
+
+// loadGerman loads the de-1996 fixture once per test binary.
+func loadGerman(tb testing.TB) *hyphenate.Dictionary {
+	tb.Helper()
+	german.once.Do(func() {
+		var f *os.File
+		if f, german.err = os.Open(filepath.Join("testdata", "hyph-de-1996.tex")); german.err != nil {
+			return
+		}
+		defer f.Close()
+		german.dict, german.err = tex.LoadDictionary("hyph-de-1996.tex", f)
+	})
+	if german.err != nil {
+		tb.Fatal(german.err)
+	}
+	return german.dict
+}
+
+func TestAppendBreaksMatchesBreaks(t *testing.T) {
+	dict := loadGerman(t)
+	h := dict.NewHyphenator()
+	var breaks []hyphenate.Break
+	for _, word := range append(benchWords, "GROSS", "Cafés", "") {
+		breaks = h.AppendBreaks(breaks[:0], []byte(word))
+		want := dict.Breaks(word)
+		if len(breaks) == 0 && len(want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(breaks, want) {
+			t.Errorf("breaks of %q: got %v, want %v", word, breaks, want)
+		}
+	}
+}
+
+func TestAppendBreaksDoesNotAllocate(t *testing.T) {
+	dict := loadGerman(t)
+	h := dict.NewHyphenator()
+	words := make([][]byte, len(benchWords))
+	for i, word := range benchWords {
+		words[i] = []byte(word)
+	}
+	breaks := make([]hyphenate.Break, 0, 32)
+	for _, word := range words { // warm up
+		breaks = h.AppendBreaks(breaks[:0], word)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, word := range words {
+			breaks = h.AppendBreaks(breaks[:0], word)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("AppendBreaks allocates %.1f times per run, want 0", allocs)
+	}
+}
+
+func TestMatchersAgreeOnGerman(t *testing.T) {
+	dict := loadGerman(t)
+	ac, walk := dict.Options, dict.Options
+	ac.Matcher, walk.Matcher = hyphenate.AhoCorasick, hyphenate.SuffixWalk
+	words := append([]string{}, benchWords...)
+	rnd := rand.New(rand.NewSource(1996))
+	letters := []rune("abcdefghijklmnopqrstuvwxyzäöüß")
+	for i := 0; i < 2000; i++ {
+		word := make([]rune, 2+rnd.Intn(30))
+		for j := range word {
+			word[j] = letters[rnd.Intn(len(letters))]
+		}
+		words = append(words, string(word))
+	}
+	for _, word := range words {
+		got, want := dict.BreaksWith(word, ac), dict.BreaksWith(word, walk)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("breaks of %q differ: Aho-Corasick %v, suffix walk %v", word, got, want)
+		}
+	}
+}
+
+func BenchmarkBreaks(b *testing.B) {
+	dict := loadGerman(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dict.Breaks(benchWords[i%len(benchWords)])
+	}
+}
+
+func BenchmarkAppendBreaks(b *testing.B) {
+	dict := loadGerman(b)
+	h := dict.NewHyphenator()
+	words := make([][]byte, len(benchWords))
+	for i, word := range benchWords {
+		words[i] = []byte(word)
+	}
+	breaks := make([]hyphenate.Break, 0, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		breaks = h.AppendBreaks(breaks[:0], words[i%len(words)])
+	}
+}
+
+func benchmarkMatcher(b *testing.B, m hyphenate.Matcher, words []string) {
+	dict := loadGerman(b)
+	h := dict.NewHyphenator()
+	h.Options.Matcher = m
+	input := make([][]byte, len(words))
+	for i, word := range words {
+		input[i] = []byte(word)
+	}
+	breaks := make([]hyphenate.Break, 0, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		breaks = h.AppendBreaks(breaks[:0], input[i%len(input)])
+	}
+}
+
+var longCompounds = []string{
+	"Donaudampfschifffahrtsgesellschaftskapitän",
+	"Rindfleischetikettierungsüberwachungsaufgabenübertragungsgesetz",
+	"Grundstücksverkehrsgenehmigungszuständigkeitsübertragungsverordnung",
+}
+
+func BenchmarkAhoCorasick(b *testing.B) { benchmarkMatcher(b, hyphenate.AhoCorasick, benchWords) }
+func BenchmarkSuffixWalk(b *testing.B)  { benchmarkMatcher(b, hyphenate.SuffixWalk, benchWords) }
+
+func BenchmarkAhoCorasickLongCompounds(b *testing.B) {
+	benchmarkMatcher(b, hyphenate.AhoCorasick, longCompounds)
+}
+
+func BenchmarkSuffixWalkLongCompounds(b *testing.B) {
+	benchmarkMatcher(b, hyphenate.SuffixWalk, longCompounds)
+}
@@ -0,0 +1,50 @@
+// Package de1996 bundles the hyph-utf8 hyphenation patterns for German in
+// reformed orthography (de-1996). Importing it registers language "de-1996"
+// with package langs.
+package de1996
+
+import (
+	"bytes"
+	_ "embed"
+
+	"golang.org/x/text/language"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/langs"
+)
+
+// Tag is the hyph-utf8 language tag of this package.
+const Tag = "de-1996"
+
+//go:embed hyph-de-1996.hyd
+var compiled []byte
+
+// License is the licence text of the patterns.
+//
+//go:embed hyph-de-1996.lic.txt
+var License string
+
+func init() {
+	metadata, err := hyphenate.CompiledMetadata(compiled)
+	if err != nil {
+		panic("de1996: " + err.Error())
+	}
+	langs.Register(hyphenate.Language{
+		Tag:      language.MustParse(Tag),
+		Name:     "German, reformed spelling",
+		License:  License,
+		Metadata: metadata,
+	}, Dictionary)
+}
+
+// Dictionary decodes the bundled dictionary. Every call returns a new
+// dictionary.
+func Dictionary() (*hyphenate.Dictionary, error) {
+	return hyphenate.ReadDictionary(bytes.NewReader(compiled))
+}
+
+// Compiled returns the embedded compiled dictionary (see
+// hyphenate.ReadDictionary). The result must not be modified.
+func Compiled() []byte {
+	return compiled
+}
@@ -0,0 +1,50 @@
+// Package enus bundles the hyph-utf8 hyphenation patterns and exceptions for
+// American English (en-us). Importing it registers language "en-us" with
+// package langs.
+package enus
+
+import (
+	"bytes"
+	_ "embed"
+
+	"golang.org/x/text/language"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/langs"
+)
+
+// Tag is the hyph-utf8 language tag of this package.
+const Tag = "en-us"
+
+//go:embed hyph-en-us.hyd
+var compiled []byte
+
+// License is the licence text of the patterns.
+//
+//go:embed hyph-en-us.lic.txt
+var License string
+
+func init() {
+	metadata, err := hyphenate.CompiledMetadata(compiled)
+	if err != nil {
+		panic("enus: " + err.Error())
+	}
+	langs.Register(hyphenate.Language{
+		Tag:      language.MustParse(Tag),
+		Name:     "English, American spelling",
+		License:  License,
+		Metadata: metadata,
+	}, Dictionary)
+}
+
+// Dictionary decodes the bundled dictionary. Every call returns a new
+// dictionary.
+func Dictionary() (*hyphenate.Dictionary, error) {
+	return hyphenate.ReadDictionary(bytes.NewReader(compiled))
+}
+
+// Compiled returns the embedded compiled dictionary (see
+// hyphenate.ReadDictionary). The result must not be modified.
+func Compiled() []byte {
+	return compiled
+}
@@ -0,0 +1,75 @@
+// Command gen compiles the hyph-utf8 sources of the bundled languages into
+// the sub-packages of package langs. Run it with "go generate" in directory
+// langs.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+// languages maps hyph-utf8 tags to the sub-packages bundling them.
+var languages = []struct {
+	tag, pkg string
+}{
+	{"de-1996", "de1996"},
+	{"en-us", "enus"},
+}
+
+func main() {
+	src := flag.String("src", "testdata", "directory holding hyph-<tag>.tex files")
+	lines := flag.Bool("lines", false, "keep the source lines of the patterns (see hyphenate.CompileOptions)")
+	flag.Parse()
+	opts := hyphenate.CompileOptions{SourceLines: *lines}
+	for _, lang := range languages {
+		if err := compile(*src, lang.tag, lang.pkg, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "gen: %s: %v\n", lang.tag, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func compile(src, tag, pkg string, opts hyphenate.CompileOptions) error {
+	name := "hyph-" + tag + ".tex"
+	data, err := os.ReadFile(filepath.Join(src, name))
+	if err != nil {
+		return err
+	}
+	dict, err := tex.LoadDictionary(name, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err = dict.WriteCompiled(&buf, opts); err != nil {
+		return err
+	}
+	base := filepath.Join(pkg, "hyph-"+tag)
+	if err = os.WriteFile(base+".hyd", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(base+".lic.txt", licence(data), 0o644)
+}
+
+// licence extracts the comment header of a hyph-utf8 file, which holds
+// title, copyright and licence, in the form of hyph-utf8's .lic.txt files.
+func licence(data []byte) []byte {
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, ok := strings.CutPrefix(scanner.Text(), "%")
+		if !ok || strings.HasPrefix(strings.TrimSpace(line), "===") {
+			break
+		}
+		buf.WriteString(strings.TrimPrefix(line, " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
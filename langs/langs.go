@@ -0,0 +1,66 @@
+// Package langs provides hyphenation dictionaries which are bundled with the
+// binary, so no pattern files are needed at runtime.
+//
+// Every language lives in a sub-package of its own, which embeds a compiled
+// dictionary (see hyphenate.ReadDictionary) together with its licence text.
+// Sub-packages register themselves on import, so only the languages a program
+// imports are linked:
+//
+//	import (
+//		"github.com/npillmayer/hyphenate/langs"
+//		_ "github.com/npillmayer/hyphenate/langs/de1996"
+//	)
+//
+//	dict, err := langs.Load("de-1996")
+//
+// Language tags follow BCP 47, with fallbacks for languages which are not
+// bundled, e.g. de-CH → de-1996.
+//
+// The compiled dictionaries are generated from the hyph-utf8 sources in
+// directory testdata with "go generate".
+package langs
+
+//go:generate go run ./internal/gen -src ../testdata
+
+import (
+	"golang.org/x/text/language"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// Registry holds the languages of all imported sub-packages. Base languages
+// are aliased to their preferred variants, i.e. de to de-1996, en to en-US
+// and en-001 (English outside the US) to en-GB, so requests fall back
+// sensibly (see hyphenate.Registry).
+var Registry = newRegistry()
+
+func newRegistry() *hyphenate.Registry {
+	reg := hyphenate.NewRegistry()
+	reg.Alias(language.German, language.MustParse("de-1996"))
+	reg.Alias(language.English, language.AmericanEnglish)
+	reg.Alias(language.MustParse("en-001"), language.BritishEnglish)
+	return reg
+}
+
+// Register makes a language available to Load. It is called by the
+// language sub-packages on initialization and panics if the tag is already
+// registered.
+func Register(lang hyphenate.Language, load func() (*hyphenate.Dictionary, error)) {
+	if err := Registry.Register(lang, load); err != nil {
+		panic("langs: " + err.Error())
+	}
+}
+
+// Load returns the dictionary for a BCP 47 language tag, or for its closest
+// fallback. The dictionary is decoded on first use and shared by all callers
+// afterwards; it is safe for concurrent use. The language's sub-package must
+// have been imported.
+func Load(tag string) (*hyphenate.Dictionary, error) {
+	return Registry.Load(tag)
+}
+
+// Languages returns all imported languages, with their names, licence texts
+// and source metadata, ordered by tag.
+func Languages() []hyphenate.Language {
+	return Registry.Languages()
+}
@@ -0,0 +1,115 @@
+package langs_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/langs"
+	"github.com/npillmayer/hyphenate/langs/de1996"
+	"github.com/npillmayer/hyphenate/langs/enus"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+func TestLoad(t *testing.T) {
+	var tags []string
+	for _, lang := range langs.Languages() {
+		tags = append(tags, lang.Tag.String())
+	}
+	if !reflect.DeepEqual(tags, []string{"de-1996", "en-US"}) {
+		t.Fatalf("unexpected languages %v", tags)
+	}
+	dict, err := langs.Load("de-1996")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := dict.HyphenationString("Fürsorge"); h != "Für-sor-ge" {
+		t.Errorf("Fürsorge should be Für-sor-ge, is %s", h)
+	}
+	if again, _ := langs.Load("de-1996"); again != dict {
+		t.Errorf("expected Load to return a shared dictionary")
+	}
+	if e := dict.Explain("Fürsorge"); len(e.Matches) == 0 || e.Matches[0].Line != 0 {
+		t.Errorf("expected explanation without source lines, is\n%s", e)
+	}
+	dict, err = langs.Load("en-us")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := dict.HyphenationString("table"); h != "ta-ble" { // from exceptions
+		t.Errorf("table should be ta-ble, is %s", h)
+	}
+	if _, err = langs.Load("fr"); !errors.Is(err, hyphenate.ErrUnknownLanguage) {
+		t.Errorf("expected ErrUnknownLanguage for unknown language, got %v", err)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"de", "de-1996"},
+		{"de-CH", "de-1996"},
+		{"de-AT-1996", "de-1996"},
+		{"en", "en-US"},
+		{"en-GB", "en-US"}, // no en-GB bundled
+		{"en-AU", "en-US"},
+	}
+	for _, tt := range tests {
+		lang, ok := langs.Registry.Match(language.MustParse(tt.tag))
+		if !ok || lang.Tag.String() != tt.want {
+			t.Errorf("expected %s to fall back to %s, got %v", tt.tag, tt.want, lang.Tag)
+		}
+	}
+}
+
+func TestLicense(t *testing.T) {
+	lang, ok := langs.Registry.Match(language.MustParse("de-1996"))
+	if !ok {
+		t.Fatal("de-1996 not registered")
+	}
+	if !strings.Contains(lang.License, "Permission is hereby granted") {
+		t.Errorf("licence text of de-1996 is missing, have %q", lang.License)
+	}
+	if md := lang.Metadata; md == nil || md.Tag != "de-1996" || md.Version == "" || len(md.Licenses) == 0 {
+		t.Errorf("metadata of de-1996 is incomplete, have %+v", md)
+	}
+}
+
+// TestUpToDate checks that the embedded dictionaries match a fresh
+// compilation of their sources, i.e. that "go generate" has been run after
+// changes to the sources or to the compiled format.
+func TestUpToDate(t *testing.T) {
+	for _, lang := range []struct {
+		tag      string
+		compiled []byte
+	}{
+		{de1996.Tag, de1996.Compiled()},
+		{enus.Tag, enus.Compiled()},
+	} {
+		name := "hyph-" + lang.tag + ".tex"
+		data, err := os.ReadFile(filepath.Join("..", "testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dict, err := tex.LoadDictionary(name, bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want bytes.Buffer
+		if _, err = dict.WriteTo(&want); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(lang.compiled, want.Bytes()) {
+			t.Errorf("bundled dictionary %s is out of date, run go generate", lang.tag)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package hyphenate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// LetterReader may be implemented by a PatternReader whose source declares
+// letters of its alphabet explicitly, together with their lowercase forms,
+// e.g. by TeX \lccode assignments. LoadPatterns queries it after the pattern
+// stream is exhausted.
+//
+// Declared letters extend Unicode's: characters such as the apostrophe become
+// part of words, and the declared lowercase forms take precedence over
+// Unicode case folding.
+type LetterReader interface {
+	Letters() map[rune]rune // letter => lowercase form
+}
+
+// IsLetter reports whether r is a letter for the dictionary, i.e. a Unicode
+// letter or a letter declared by the pattern source (see LetterReader).
+func (dict *Dictionary) IsLetter(r rune) bool {
+	if dict != nil {
+		if _, ok := dict.letters[r]; ok {
+			return true
+		}
+	}
+	return unicode.IsLetter(r)
+}
+
+// toLower maps r to lowercase, using the declared lowercase form if r is a
+// declared letter.
+func (dict *Dictionary) toLower(r rune) rune {
+	if lower, ok := dict.letters[r]; ok {
+		return lower
+	}
+	return unicode.ToLower(r)
+}
+
+// foldLetters is like foldCase, but applies the declared lowercase
+// forms of the dictionary's letters.
+func (dict *Dictionary) foldLetters(word string) (lower string, allCaps bool) {
+	lower, allCaps = foldCase(word)
+	if dict == nil || len(dict.letters) == 0 {
+		return lower, allCaps
+	}
+	return strings.Map(dict.toLower, word), allCaps
+}
+
+// setLetters stores the declared letters of a pattern source. Letters
+// declared as non-letters (with a lowercase form of 0) are dropped.
+func (dict *Dictionary) setLetters(letters map[rune]rune) {
+	for letter, lower := range letters {
+		if lower == 0 {
+			continue
+		}
+		if dict.letters == nil {
+			dict.letters = make(map[rune]rune, len(letters))
+		}
+		dict.letters[letter] = lower
+	}
+}
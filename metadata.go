@@ -0,0 +1,58 @@
+package hyphenate
+
+// Metadata describes the source of a dictionary: title, language, version,
+// authors and licences. It follows the comment headers of hyph-utf8 pattern
+// files, e.g.
+//
+//	% title: Hyphenation patterns for American English
+//	% language:
+//	%     name: English, American spelling
+//	%     tag: en-us
+//	% version: 2005-05-30
+//	% hyphenmins:
+//	%     typesetting:
+//	%         left: 2
+//	%         right: 3
+//
+// Fields missing from the source are left empty.
+type Metadata struct {
+	Title     string
+	Language  string // English name of the language, e.g. "German, reformed spelling"
+	Tag       string // language tag of the source, e.g. "de-1996"
+	Version   string
+	Notice    string
+	Copyright string
+	Source    string // where the patterns have been generated from
+	Authors   []Author
+	Licenses  []License
+
+	GenerationMins  Hyphenmins // hyphenmins the patterns have been generated with
+	TypesettingMins Hyphenmins // hyphenmins recommended for typesetting
+}
+
+// Author is an author of a pattern source.
+type Author struct {
+	Name    string
+	Contact string
+}
+
+// License is a licence a pattern source is distributed under. Sources may
+// carry more than one, e.g. "MIT" and "LPPL".
+type License struct {
+	Name string
+	URL  string
+	Text string
+}
+
+// Hyphenmins are the minimum number of characters before the first and after
+// the last hyphen of a word. Values of 0 mean "unknown".
+type Hyphenmins struct {
+	Left, Right int
+}
+
+// MetadataReader may be implemented by a PatternReader which knows about the
+// metadata of its source. LoadPatterns queries it after the pattern stream is
+// exhausted and attaches the result to the dictionary.
+type MetadataReader interface {
+	Metadata() *Metadata
+}
@@ -0,0 +1,56 @@
+package hyphenate
+
+import (
+	"os"
+)
+
+// OpenDictionary opens a compiled dictionary file, as written by
+// Dictionary.WriteTo.
+//
+// Where supported (currently on Linux), the file is memory-mapped read-only
+// and shared: the trie and the pattern store are used in place, without
+// private copies, so processes using the same file share its pages. On other
+// platforms, or if mapping fails, the file is read into memory.
+//
+// Opening is not lazy: the checksum and the consistency checks of
+// ReadDictionary read the whole file once, faulting in every page of the
+// mapping. The pages belong to the shared page cache, though, not to the
+// process, so a second process opening the file reads them from memory.
+//
+// Clients should call Close to release the mapping when the dictionary is no
+// longer in use.
+func OpenDictionary(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if nativeLittleEndian { // otherwise arrays have to be copied anyway
+		data, err := mmapFile(f)
+		if err == nil {
+			dict, err := decodeDictionary(data)
+			if err != nil {
+				munmap(data)
+				return nil, err
+			}
+			dict.mapped = data
+			return dict, nil
+		}
+		tracer().Infof("cannot memory-map %s (%v), reading it instead", path, err)
+	}
+	return ReadDictionary(f)
+}
+
+// Close releases the memory mapping of a dictionary opened by
+// OpenDictionary. The dictionary must not be used after Close; it will not
+// find any further hyphenation opportunities. Close must not be called
+// concurrently with other uses of the dictionary. For dictionaries which are
+// not memory-mapped, Close is a no-op.
+func (dict *Dictionary) Close() error {
+	if dict == nil || dict.mapped == nil {
+		return nil
+	}
+	data := dict.mapped
+	dict.mapped, dict.patterns, dict.patternsV, dict.nextLevel = nil, nil, nil, nil
+	return munmap(data)
+}
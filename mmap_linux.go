@@ -0,0 +1,26 @@
+//go:build linux
+
+package hyphenate
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// mmapFile maps file f read-only and shared.
+func mmapFile(f *os.File) ([]byte, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size <= 0 || int64(int(size)) != size {
+		return nil, errors.New("file size not suitable for mapping")
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package hyphenate
+
+import (
+	"errors"
+	"os"
+)
+
+// mmapFile is not supported on this platform; OpenDictionary falls back to
+// reading the file.
+func mmapFile(f *os.File) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func munmap(data []byte) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package hyphenate
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizedWord is a word in Unicode normalization form NFC, which is the
+// form of hyph-utf8 patterns, together with a mapping back to the word as
+// given by the caller.
+type normalizedWord struct {
+	orig string
+	nfc  string
+	// offsets[k] is the byte offset in orig where NFC rune k starts, or -1 if
+	// rune k is not the first rune of a normalization segment (e.g. a combining
+	// mark). The final entry is len(orig). offsets is nil if nfc == orig.
+	offsets []int
+}
+
+// normalizeWord converts word to NFC. Normalization segments, i.e. a base
+// character followed by its combining marks, are normalized one by one, so
+// every rune of the result can be traced back to a segment of word.
+func normalizeWord(word string) normalizedWord {
+	w := normalizedWord{orig: word, nfc: word}
+	if norm.NFC.IsNormalString(word) {
+		return w
+	}
+	buf := make([]byte, 0, len(word))
+	w.offsets = make([]int, 0, len(word)+1)
+	for i := 0; i < len(word); {
+		j := i + norm.NFC.NextBoundaryInString(word[i:], true)
+		if j <= i { // cannot happen for atEOF = true, but be defensive
+			j = len(word)
+		}
+		first := len(buf)
+		buf = norm.NFC.AppendString(buf, word[i:j])
+		for k := range string(buf[first:]) {
+			if k == 0 {
+				w.offsets = append(w.offsets, i)
+			} else {
+				w.offsets = append(w.offsets, -1)
+			}
+		}
+		i = j
+	}
+	w.offsets = append(w.offsets, len(word))
+	w.nfc = string(buf)
+	return w
+}
+
+// origOffset maps rune offset k of w.nfc to a byte offset into w.orig. Offsets
+// within a normalization segment are mapped to the start of the segment.
+func (w normalizedWord) origOffset(k int) int {
+	if w.offsets == nil {
+		return runeOffset(w.orig, k)
+	}
+	k = min(k, len(w.offsets)-1)
+	for k > 0 && w.offsets[k] < 0 {
+		k--
+	}
+	return w.offsets[k]
+}
+
+// origRune maps rune offset k of w.nfc to a rune offset into w.orig.
+func (w normalizedWord) origRune(k int) int {
+	if w.offsets == nil {
+		return k
+	}
+	return utf8.RuneCountInString(w.orig[:w.origOffset(k)])
+}
+
+// mapBreaks maps the rune and byte offsets of breaks from w.nfc to w.orig.
+// Breaks within a normalization segment are dropped.
+func (w normalizedWord) mapBreaks(breaks []Break) []Break {
+	if w.offsets == nil {
+		return breaks
+	}
+	mapped := breaks[:0]
+	for _, brk := range breaks {
+		k := brk.Rune
+		if w.offsets[k] < 0 {
+			continue
+		}
+		brk.Rune, brk.Byte = w.origRune(k), w.offsets[k]
+		mapped = append(mapped, brk)
+	}
+	return mapped
+}
@@ -0,0 +1,43 @@
+package hyphenate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizationAwareLookup(t *testing.T) {
+	dict, err := LoadPatterns("normalization", &slicePatternReader{
+		entries: []Pattern{
+			{Sequence: []rune("für"), Weights: []int{0, 0, 1}}, // NFC
+			{Sequence: []rune("q"), Weights: []int{0, 1}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nfd := "fu\u0308rung" // "fürung" with combining diaeresis
+	if h := dict.HyphenationString(nfd); h != "fu\u0308-rung" {
+		t.Fatalf("%q should be fü-rung, is %q", nfd, h)
+	}
+	breaks := dict.Breaks(nfd)
+	if want := []Break{{Rune: 3, Byte: 4, Level: 1}}; !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("breaks should refer to the NFD input: got %v, want %v", breaks, want)
+	}
+	if levels := dict.Levels(nfd); !reflect.DeepEqual(levels, []int{0, 0, 0, 1, 0, 0, 0, 0}) {
+		t.Fatalf("levels should refer to the NFD input: got %v", levels)
+	}
+	if h := dict.HyphenationString("FU\u0308RUNG"); h != "FU\u0308-RUNG" {
+		t.Fatalf("upper case NFD should be hyphenated, is %q", h)
+	}
+	// "q\u0303" has no precomposed form and stays decomposed in NFC
+	if h := dict.HyphenationString("aaq\u0303bb"); h != "aaq\u0303bb" {
+		t.Fatalf("no break allowed before a combining mark, got %q", h)
+	}
+	dict.AddException("fu\u0308hrung", []int{0, 0, 0, 0, 1, 0, 0, 0})
+	if h := dict.HyphenationString("führung"); h != "füh-rung" {
+		t.Fatalf("NFD exception should match NFC input, got %q", h)
+	}
+	if h := dict.HyphenationString("fu\u0308hrung"); h != "fu\u0308h-rung" {
+		t.Fatalf("NFD exception should match NFD input, got %q", h)
+	}
+}
@@ -0,0 +1,66 @@
+package patgen
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// Load builds a dictionary from the generated patterns, with the hyphenmins
+// they have been generated for.
+func (res *Result) Load(name string) (*hyphenate.Dictionary, error) {
+	return hyphenate.LoadPatterns(name, res.Reader())
+}
+
+// Reader returns a reader for the generated patterns. It implements
+// hyphenate.HyphenminsReader.
+func (res *Result) Reader() hyphenate.PatternReader {
+	return &patternReader{patterns: res.Patterns, left: res.LeftMin, right: res.RightMin}
+}
+
+// patternReader streams a slice of patterns.
+type patternReader struct {
+	patterns    []hyphenate.Pattern
+	left, right int
+}
+
+func (r *patternReader) Next() ([]rune, []int, error) {
+	if len(r.patterns) == 0 {
+		return nil, nil, io.EOF
+	}
+	p := r.patterns[0]
+	r.patterns = r.patterns[1:]
+	return p.Sequence, p.Weights, nil
+}
+
+func (r *patternReader) Hyphenmins() (left, right int) {
+	return r.left, r.right
+}
+
+// WriteTeX writes the generated patterns as a TeX pattern file, which
+// texpatterns.LoadPatterns reads. The file starts with a hyph-utf8 header
+// holding the hyphenmins, followed by
+//
+//	\patterns{
+//	.ab4
+//	a1b
+//	…
+//	}
+func (res *Result) WriteTeX(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%% hyphenmins:\n"+
+		"%%     generation:\n%%         left:  %d\n%%         right: %d\n"+
+		"%%     typesetting:\n%%         left:  %d\n%%         right: %d\n"+
+		"%% ==========\n\\patterns{\n",
+		res.LeftMin, res.RightMin, res.LeftMin, res.RightMin)
+	if err != nil {
+		return err
+	}
+	for _, p := range res.Patterns {
+		if _, err := fmt.Fprintln(w, p.String()); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "}\n")
+	return err
+}
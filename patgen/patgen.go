@@ -0,0 +1,314 @@
+// Package patgen generates Liang hyphenation patterns from a list of
+// hyphenated words, like TeX's patgen.
+//
+// Patterns are generated level by level. Odd levels select patterns which
+// find the breaks of the word list not yet found by the patterns of the lower
+// levels, even levels select patterns which inhibit the breaks found wrongly.
+// Within a level, candidate patterns are tried by increasing length, and
+// for every length by the position of their digit. A candidate is counted
+// as good at every position of the word list it would fix and as bad at every
+// position it would spoil; it is selected if
+//
+//	good*GoodWeight - bad*BadWeight >= Threshold
+//
+// Positions where a pattern of the current level has been selected, or where
+// a candidate is hopeless (good*GoodWeight < Threshold), are not considered
+// for longer candidates of the same level.
+package patgen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/npillmayer/hyphenate"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Level holds the parameters of one level of pattern generation. They
+// correspond to patgen's pat_start, pat_finish, good_wt, bad_wt and thresh.
+type Level struct {
+	MinLength  int // length of the shortest patterns, in runes; edge markers '.' count
+	MaxLength  int // length of the longest patterns
+	GoodWeight int // weight of a position which a pattern fixes
+	BadWeight  int // weight of a position which a pattern spoils
+	Threshold  int // minimum score of a selected pattern
+}
+
+// Params control pattern generation.
+type Params struct {
+	Levels   []Level // parameters of levels 1, 2, …; at most 9 levels
+	LeftMin  int     // breaks with fewer runes before them are ignored; 0 means hyphenate.DefaultLeftMin
+	RightMin int     // breaks with fewer runes after them are ignored; 0 means hyphenate.DefaultRightMin
+}
+
+// Stats describe the patterns after a level of pattern generation, measured
+// against the word list.
+type Stats struct {
+	Level    int // 1, 2, …
+	Patterns int // number of patterns selected at this level
+	Good     int // breaks of the word list found by the patterns
+	Bad      int // breaks found by the patterns which are not in the word list
+	Missed   int // breaks of the word list not found by the patterns
+}
+
+// Result holds generated patterns.
+type Result struct {
+	Patterns []hyphenate.Pattern // sorted by sequence
+	Stats    []Stats             // by level
+	LeftMin  int                 // hyphenmins the patterns have been generated for
+	RightMin int
+}
+
+// Generate generates patterns for the words of reader, e.g. a
+// txt.ExceptionReader for a list with one hyphenated word per line ("ta-ble").
+// Words are folded to lowercase and must be in Unicode normalization form NFC.
+//
+// Example:
+//
+//	res, err := patgen.Generate(txt.NewExceptionReader(f), patgen.Params{
+//		Levels: []patgen.Level{
+//			{MinLength: 2, MaxLength: 5, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+//			{MinLength: 2, MaxLength: 5, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+//		},
+//	})
+func Generate(reader hyphenate.ExceptionReader, params Params) (*Result, error) {
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+	g := &generator{params: params, patterns: make(map[string][]int)}
+	if err := g.readWords(reader); err != nil {
+		return nil, err
+	}
+	res := &Result{LeftMin: params.LeftMin, RightMin: params.RightMin}
+	for i, level := range params.Levels {
+		n := g.generateLevel(i+1, level)
+		stats, err := g.evaluate()
+		if err != nil {
+			return nil, err
+		}
+		stats.Level, stats.Patterns = i+1, n
+		res.Stats = append(res.Stats, stats)
+	}
+	res.Patterns = g.result()
+	return res, nil
+}
+
+// check validates params and sets default hyphenmins.
+func (params *Params) check() error {
+	if len(params.Levels) == 0 || len(params.Levels) > 9 {
+		return fmt.Errorf("number of levels should be 1 to 9, is %d", len(params.Levels))
+	}
+	for i, l := range params.Levels {
+		switch {
+		case l.MinLength < 1 || l.MaxLength < l.MinLength:
+			return fmt.Errorf("level %d: invalid pattern lengths %d to %d", i+1, l.MinLength, l.MaxLength)
+		case l.GoodWeight < 1 || l.BadWeight < 0 || l.Threshold < 1:
+			return fmt.Errorf("level %d: weights and threshold should be positive", i+1)
+		}
+	}
+	if params.LeftMin <= 0 {
+		params.LeftMin = hyphenate.DefaultLeftMin
+	}
+	if params.RightMin <= 0 {
+		params.RightMin = hyphenate.DefaultRightMin
+	}
+	return nil
+}
+
+// generator holds the state of pattern generation.
+type generator struct {
+	params   Params
+	words    []word
+	patterns map[string][]int // sequence => weights, with len(sequence)+1 entries
+}
+
+// word is a word of the word list.
+type word struct {
+	text   string // lowercase
+	dotted []rune // ".text."
+	breaks []bool // breaks[i] is true for a break before rune i in the word list
+	levels []int  // levels of the patterns generated so far, see hyphenate.Dictionary.Levels
+	noMore []bool // positions decided at the current level
+}
+
+// readWords reads the word list.
+func (g *generator) readWords(reader hyphenate.ExceptionReader) error {
+	for {
+		text, positions, err := reader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if !norm.NFC.IsNormalString(text) {
+			return fmt.Errorf("word %q is not in NFC", text)
+		}
+		runes := []rune(text)
+		for i, r := range runes {
+			runes[i] = unicode.ToLower(r)
+		}
+		w := word{
+			text:   string(runes),
+			dotted: append(append([]rune{'.'}, runes...), '.'),
+			breaks: make([]bool, len(runes)+1),
+			levels: make([]int, len(runes)+1),
+			noMore: make([]bool, len(runes)+1),
+		}
+		for i, p := range positions {
+			if i < len(w.breaks) && p%2 != 0 {
+				w.breaks[i] = true
+			}
+		}
+		g.words = append(g.words, w)
+	}
+	if len(g.words) == 0 {
+		return errors.New("empty word list")
+	}
+	return nil
+}
+
+// count holds the good and bad positions of a candidate pattern.
+type count struct {
+	good, bad int
+}
+
+// generateLevel selects the patterns of a level and returns their number.
+func (g *generator) generateLevel(level int, params Level) int {
+	selected := 0
+	for length := params.MinLength; length <= params.MaxLength; length++ {
+		for _, dot := range dotOrder(length) {
+			counts := make(map[string]*count)
+			g.candidates(level, length, dot, func(_ *word, _ int, key string, good bool) {
+				c := counts[key]
+				if c == nil {
+					c = &count{}
+					counts[key] = c
+				}
+				if good {
+					c.good++
+				} else {
+					c.bad++
+				}
+			})
+			decided := make(map[string]bool)
+			for key, c := range counts {
+				switch {
+				case c.good*params.GoodWeight-c.bad*params.BadWeight >= params.Threshold:
+					g.insert(key, dot, level)
+					decided[key] = true
+					selected++
+				case c.good*params.GoodWeight < params.Threshold:
+					decided[key] = true // hopeless, as are its extensions
+				}
+			}
+			g.candidates(level, length, dot, func(w *word, i int, key string, _ bool) {
+				if decided[key] {
+					w.noMore[i] = true
+				}
+			})
+		}
+	}
+	return selected
+}
+
+// dotOrder returns the positions of the digit within patterns of length n,
+// from the middle outwards, in the order of patgen.
+func dotOrder(n int) []int {
+	var dots []int
+	dot, dot1 := n/2, n/2*2
+	for {
+		dot, dot1 = dot1-dot, 2*n-dot1-1
+		dots = append(dots, dot)
+		if dot == n {
+			return dots
+		}
+	}
+}
+
+// candidates calls f for every position of the word list which a pattern of
+// a level, of length runes and with its digit at dot, might fix (good) or
+// spoil (!good). key is the pattern's sequence.
+func (g *generator) candidates(level, length, dot int, f func(w *word, i int, key string, good bool)) {
+	for k := range g.words {
+		w := &g.words[k]
+		n := len(w.dotted) - 2
+		for i := g.params.LeftMin; i <= n-g.params.RightMin; i++ {
+			if w.noMore[i] {
+				continue
+			}
+			found := w.levels[i]%2 != 0
+			var good, bad bool
+			if level%2 != 0 { // hyphenating level
+				good, bad = w.breaks[i] && !found, !w.breaks[i] && !found
+			} else { // inhibiting level
+				good, bad = !w.breaks[i] && found, w.breaks[i] && found
+			}
+			// the break before rune i of the word is located before dotted rune i+1
+			start := i + 1 - dot
+			if !(good || bad) || start < 0 || start+length > len(w.dotted) {
+				continue
+			}
+			f(w, i, string(w.dotted[start:start+length]), good)
+		}
+	}
+}
+
+// insert adds a digit for level at dot to the pattern with sequence key.
+func (g *generator) insert(key string, dot, level int) {
+	weights := g.patterns[key]
+	if weights == nil {
+		weights = make([]int, utf8.RuneCountInString(key)+1)
+		g.patterns[key] = weights
+	}
+	weights[dot] = max(weights[dot], level)
+}
+
+// evaluate computes the levels of the words for the patterns generated so
+// far and resets the positions decided at the current level.
+func (g *generator) evaluate() (Stats, error) {
+	dict, err := hyphenate.LoadPatterns("patgen", &patternReader{patterns: g.result()})
+	if err != nil {
+		return Stats{}, err
+	}
+	var stats Stats
+	for k := range g.words {
+		w := &g.words[k]
+		w.levels = dict.Levels(w.text)
+		clear(w.noMore)
+		n := len(w.dotted) - 2
+		for i := g.params.LeftMin; i <= n-g.params.RightMin; i++ {
+			found := w.levels[i]%2 != 0
+			switch {
+			case found && w.breaks[i]:
+				stats.Good++
+			case found:
+				stats.Bad++
+			case w.breaks[i]:
+				stats.Missed++
+			}
+		}
+	}
+	return stats, nil
+}
+
+// result returns the patterns generated so far, sorted by sequence.
+func (g *generator) result() []hyphenate.Pattern {
+	keys := make([]string, 0, len(g.patterns))
+	for key := range g.patterns {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	patterns := make([]hyphenate.Pattern, len(keys))
+	for i, key := range keys {
+		seq, weights := []rune(key), g.patterns[key]
+		if weights[len(seq)] == 0 {
+			weights = weights[:len(seq)]
+		}
+		patterns[i] = hyphenate.Pattern{Sequence: seq, Weights: slices.Clone(weights)}
+	}
+	return patterns
+}
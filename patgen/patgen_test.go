@@ -0,0 +1,117 @@
+package patgen
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate/tex/texpatterns"
+	"github.com/npillmayer/hyphenate/txt"
+)
+
+const wordList = `
+ta-ble ca-ble fa-ble sta-ble
+com-put-er com-pute com-plete com-mon
+hy-phen-ate hy-phen-a-tion na-tion ra-tion sta-tion
+pat-tern pat-terns lat-ter bet-ter let-ter
+al-go-rithm al-go-rithms rhythm
+`
+
+var params = Params{
+	Levels: []Level{
+		{MinLength: 1, MaxLength: 3, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+		{MinLength: 1, MaxLength: 4, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+		{MinLength: 2, MaxLength: 5, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+		{MinLength: 2, MaxLength: 6, GoodWeight: 1, BadWeight: 1, Threshold: 1},
+	},
+}
+
+func TestGenerate(t *testing.T) {
+	res, err := Generate(txt.NewExceptionReader(strings.NewReader(wordList)), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Stats) != 4 {
+		t.Fatalf("expected stats for 4 levels, have %d", len(res.Stats))
+	}
+	last := res.Stats[len(res.Stats)-1]
+	if last.Bad != 0 || last.Missed != 0 {
+		t.Fatalf("patterns should reproduce the word list, have %+v", last)
+	}
+	dict, err := res.Load("generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range strings.Fields(wordList) {
+		if h := dict.HyphenationString(strings.ReplaceAll(want, "-", "")); h != want {
+			t.Fatalf("word should be hyphenated as %s, is %s", want, h)
+		}
+	}
+	t.Logf("%d patterns, stats %+v", len(res.Patterns), res.Stats)
+}
+
+func TestWriteTeX(t *testing.T) {
+	res, err := Generate(txt.NewExceptionReader(strings.NewReader(wordList)), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := res.WriteTeX(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dict, err := texpatterns.LoadPatterns("generated.tex", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Options.LeftMin != 2 || dict.Options.RightMin != 2 {
+		t.Fatalf("hyphenmins should be 2/2, are %d/%d", dict.Options.LeftMin, dict.Options.RightMin)
+	}
+	if h := dict.HyphenationString("hyphenation"); h != "hy-phen-a-tion" {
+		t.Fatalf("hyphenation should be hy-phen-a-tion, is %s", h)
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	// with a high threshold, no pattern covers enough breaks
+	p := Params{Levels: []Level{{MinLength: 1, MaxLength: 3, GoodWeight: 1, BadWeight: 1, Threshold: 100}}}
+	res, err := Generate(txt.NewExceptionReader(strings.NewReader(wordList)), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Patterns) != 0 {
+		t.Fatalf("expected no patterns, have %v", res.Patterns)
+	}
+	if s := res.Stats[0]; s.Good != 0 || s.Missed == 0 {
+		t.Fatalf("all breaks should be missed, have %+v", s)
+	}
+}
+
+func TestParams(t *testing.T) {
+	for _, p := range []Params{
+		{},
+		{Levels: make([]Level, 10)},
+		{Levels: []Level{{MinLength: 3, MaxLength: 2, GoodWeight: 1, Threshold: 1}}},
+		{Levels: []Level{{MinLength: 1, MaxLength: 2, GoodWeight: 0, Threshold: 1}}},
+	} {
+		if _, err := Generate(txt.NewExceptionReader(strings.NewReader(wordList)), p); err == nil {
+			t.Fatalf("expected error for params %+v", p)
+		}
+	}
+	if _, err := Generate(txt.NewExceptionReader(strings.NewReader("")), params); err == nil {
+		t.Fatalf("expected error for empty word list")
+	}
+}
+
+func TestDotOrder(t *testing.T) {
+	for n, want := range map[int][]int{
+		1: {0, 1},
+		2: {1, 0, 2},
+		3: {1, 2, 0, 3},
+		4: {2, 1, 3, 0, 4},
+	} {
+		if dots := dotOrder(n); !slices.Equal(dots, want) {
+			t.Fatalf("dots for length %d should be %v, are %v", n, want, dots)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package hyphenate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Replacement describes the text change of a non-standard hyphenation break,
+// as introduced by László Németh for libhyphen. Examples are German
+// "Schiffahrt" => "Schiff-fahrt" (old orthography) or Hungarian
+// "ssz" => "sz-sz".
+//
+// If the break is taken, Cut runes of the word are replaced by Pre, a hyphen,
+// the line break, and Post. The replaced runes start at rune offset Start of
+// the pattern's Sequence.
+type Replacement struct {
+	Pre   string // text before the hyphen, ending the line
+	Post  string // text starting the next line
+	Start int    // offset of the first replaced rune in Pattern.Sequence
+	Cut   int    // number of replaced runes
+}
+
+// ParsePattern parses a pattern in Liang's notation, e.g. "a1b", ".ab4c",
+// or "4ab.". Digits belong to the character immediately after them, i.e.
+//
+//	"a5ban" => (a)(5b)(a)(n) => weights [0,5,0,0].
+//
+// Non-standard patterns are written in libhyphen syntax
+//
+//	pattern/replacement,start,cut
+//
+// where replacement contains '=' at the position of the hyphen, start is the
+// 1-based position of the first replaced letter within the pattern (not
+// counting a leading '.') and cut is the number of replaced letters. If start
+// and cut are omitted, the whole pattern is replaced. Example:
+//
+//	"s1sz/sz=sz,1,3" => Hungarian "ssz" is hyphenated as "sz-sz".
+func ParsePattern(s string) (Pattern, error) {
+	text, repl, nonStandard := strings.Cut(s, "/")
+	var p Pattern
+	wasDigit := false
+	for _, ch := range text {
+		if ch >= '0' && ch <= '9' {
+			if wasDigit {
+				return Pattern{}, fmt.Errorf("consecutive digits in pattern %q", s)
+			}
+			p.Weights = append(p.Weights, int(ch-'0'))
+			wasDigit = true
+			continue
+		}
+		p.Sequence = append(p.Sequence, ch)
+		if wasDigit {
+			wasDigit = false
+		} else {
+			p.Weights = append(p.Weights, 0)
+		}
+	}
+	if len(p.Sequence) == 0 {
+		return Pattern{}, fmt.Errorf("pattern %q has no letters", s)
+	}
+	if !nonStandard {
+		return p, nil
+	}
+	rep, err := parseReplacement(repl, p.Sequence)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("pattern %q: %w", s, err)
+	}
+	p.Replacement = rep
+	return p, nil
+}
+
+func parseReplacement(s string, sequence []rune) (*Replacement, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 1 && len(fields) != 3 {
+		return nil, fmt.Errorf("malformed replacement %q", s)
+	}
+	pre, post, found := strings.Cut(fields[0], "=")
+	if !found {
+		return nil, fmt.Errorf("replacement %q lacks '='", fields[0])
+	}
+	letters, skip := len(sequence), 0
+	if sequence[0] == '.' {
+		letters, skip = letters-1, 1
+	}
+	if letters > 0 && sequence[len(sequence)-1] == '.' {
+		letters--
+	}
+	start, cut := 1, letters
+	if len(fields) == 3 {
+		var err error
+		if start, err = strconv.Atoi(fields[1]); err != nil {
+			return nil, fmt.Errorf("malformed replacement start %q", fields[1])
+		}
+		if cut, err = strconv.Atoi(fields[2]); err != nil {
+			return nil, fmt.Errorf("malformed replacement cut %q", fields[2])
+		}
+	}
+	if start < 1 || cut < 0 || start-1+cut > letters {
+		return nil, fmt.Errorf("replacement range %d,%d out of pattern", start, cut)
+	}
+	return &Replacement{Pre: pre, Post: post, Start: start - 1 + skip, Cut: cut}, nil
+}
+
+// String returns the pattern in Liang's notation, with non-standard patterns
+// in libhyphen syntax.
+func (p Pattern) String() string {
+	var sb strings.Builder
+	for i, r := range p.Sequence {
+		if i < len(p.Weights) && p.Weights[i] > 0 {
+			sb.WriteString(strconv.Itoa(p.Weights[i]))
+		}
+		sb.WriteRune(r)
+	}
+	if n := len(p.Sequence); n < len(p.Weights) && p.Weights[n] > 0 {
+		sb.WriteString(strconv.Itoa(p.Weights[n]))
+	}
+	if rep := p.Replacement; rep != nil {
+		start := rep.Start + 1
+		if len(p.Sequence) > 0 && p.Sequence[0] == '.' {
+			start--
+		}
+		fmt.Fprintf(&sb, "/%s=%s,%d,%d", rep.Pre, rep.Post, start, rep.Cut)
+	}
+	return sb.String()
+}
+
+// matchCase adapts replacement text s to the casing of the replaced text
+// orig: if orig is written in capitals only, s is converted to upper case.
+func matchCase(s, orig string) string {
+	if _, allCaps := foldCase(orig); allCaps {
+		return strings.ToUpper(s)
+	}
+	return s
+}
+
+// runeOffset returns the byte offset of rune index i in s, or len(s) if s
+// has fewer runes.
+func runeOffset(s string, i int) int {
+	for b := range s {
+		if i == 0 {
+			return b
+		}
+		i--
+	}
+	return len(s)
+}
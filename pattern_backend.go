@@ -1,10 +1,5 @@
 package hyphenate
 
-// patternIterator iterates over successive prefix states for one key.
-type patternIterator interface {
-	Next(symbol uint16) int
-}
-
 type patternTrieStats struct {
 	Backend    string
 	UsedSlots  int
@@ -20,11 +15,23 @@ func (s patternTrieStats) FillRatio() float64 {
 }
 
 // patternTrie is the internal backend abstraction for pattern-key storage.
+//
+// EncodeRune, Root, Step, Next, Output and Depth are valid for frozen tries
+// only. They serve the lookup hot path and must not allocate. Next, Output and
+// Depth are the Aho-Corasick interface and require HasFailureLinks.
 type patternTrie interface {
 	EncodeKey(s string) ([]uint16, bool)
+	EncodeRune(r rune) uint16
+	Root() int
+	Step(state int, symbol uint16) int
+	Next(state int, symbol uint16) int
+	Output(state int) int
+	Depth(state int) int
+	HasFailureLinks() bool
 	AllocPositionForWord(key []uint16) int
 	ResolvePosition(pos int) int
 	Freeze()
-	Iterator() patternIterator
+	SetHyphenmins(left, right int)
+	Hyphenmins() (left, right int)
 	Stats() patternTrieStats
 }
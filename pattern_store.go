@@ -8,9 +8,11 @@ const initialPatternStoreSlots = 2 // include slot 0 + root slot
 // patternStore keeps packed hyphenation vectors directly indexed by trie position.
 // Each non-zero vector entry is stored as one byte: high nibble=index, low nibble=value.
 type patternStore struct {
-	width   uint8
-	length  []uint8 // will grow with demand
-	payload []byte  // will grow with demand
+	width        uint8
+	length       []uint8              // will grow with demand
+	payload      []byte               // will grow with demand
+	replacements map[int]*Replacement // non-standard patterns by trie position, usually empty
+	lines        []int32              // source lines by trie position, if known (see LineReader)
 }
 
 func packPositions(positions []int) ([]byte, error) {
@@ -100,6 +102,52 @@ func (s *patternStore) Packed(pos int) ([]byte, bool) {
 	return s.payload[base : base+int(n)], true
 }
 
+// PutReplacement attaches a non-standard replacement to trie position pos.
+func (s *patternStore) PutReplacement(pos int, rep *Replacement) {
+	if s.replacements == nil {
+		s.replacements = make(map[int]*Replacement)
+	}
+	s.replacements[pos] = rep
+}
+
+// Replacement returns the non-standard replacement at trie position pos, or nil.
+func (s *patternStore) Replacement(pos int) *Replacement {
+	return s.replacements[pos]
+}
+
+// PutLine records the source line of the pattern at trie position pos.
+func (s *patternStore) PutLine(pos int, line int) {
+	if pos >= len(s.lines) {
+		s.lines = append(s.lines, make([]int32, pos+1-len(s.lines))...)
+	}
+	s.lines[pos] = int32(line)
+}
+
+// Line returns the source line of the pattern at trie position pos, or 0.
+func (s *patternStore) Line(pos int) int {
+	if pos < 0 || pos >= len(s.lines) {
+		return 0
+	}
+	return int(s.lines[pos])
+}
+
+// HasReplacements reports whether any non-standard pattern is stored.
+func (s *patternStore) HasReplacements() bool {
+	return s != nil && len(s.replacements) > 0
+}
+
+// LevelAt returns the value of the payload at trie position pos for relative
+// index rel, or 0.
+func (s *patternStore) LevelAt(pos int, rel int) int {
+	packed, _ := s.Packed(pos)
+	for _, b := range packed {
+		if int(b>>4) == rel {
+			return int(b & 0x0F)
+		}
+	}
+	return 0
+}
+
 // MergeInto merges payload at trie position pos into dst at absolute offset at.
 func (s *patternStore) MergeInto(pos int, at int, dst []int) []int {
 	packed, ok := s.Packed(pos)
@@ -0,0 +1,97 @@
+package hyphenate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		text string
+		want Pattern
+	}{
+		{text: "a5ban", want: Pattern{Sequence: []rune("aban"), Weights: []int{0, 5, 0, 0}}},
+		{text: ".ab4", want: Pattern{Sequence: []rune(".ab"), Weights: []int{0, 0, 0, 4}}},
+		{text: "fü1r", want: Pattern{Sequence: []rune("für"), Weights: []int{0, 0, 1}}},
+		{text: "s1sz/sz=sz,1,3", want: Pattern{
+			Sequence:    []rune("ssz"),
+			Weights:     []int{0, 1, 0},
+			Replacement: &Replacement{Pre: "sz", Post: "sz", Start: 0, Cut: 3},
+		}},
+		{text: ".c1k/k=k", want: Pattern{
+			Sequence:    []rune(".ck"),
+			Weights:     []int{0, 0, 1},
+			Replacement: &Replacement{Pre: "k", Post: "k", Start: 1, Cut: 2},
+		}},
+	}
+	for _, tt := range tests {
+		p, err := ParsePattern(tt.text)
+		if err != nil {
+			t.Fatalf("cannot parse %q: %v", tt.text, err)
+		}
+		if !reflect.DeepEqual(p, tt.want) {
+			t.Fatalf("pattern mismatch for %q: got %+v, want %+v", tt.text, p, tt.want)
+		}
+		if tt.want.Replacement == nil && p.String() != tt.text {
+			t.Fatalf("String() should reproduce %q, is %q", tt.text, p.String())
+		}
+	}
+	if p, _ := ParsePattern(".c1k/k=k"); p.String() != ".c1k/k=k,1,2" {
+		t.Fatalf("unexpected String() for non-standard pattern: %q", p.String())
+	}
+	for _, bad := range []string{"12a", "4", "a1b/kk", "a1b/k=k,2,3", "a1b/k=k,x,1"} {
+		if _, err := ParsePattern(bad); err == nil {
+			t.Fatalf("expected error for malformed pattern %q", bad)
+		}
+	}
+}
+
+func TestNonStandardHyphenation(t *testing.T) {
+	var patterns []Pattern
+	for _, text := range []string{
+		"c1k/k=k,1,2",    // German (old orthography): "backen" => "bak-ken"
+		"ff1a/ff=f,1,2",  // German (old orthography): "Schiffahrt" => "Schiff-fahrt"
+		"s1sz/sz=sz,1,3", // Hungarian: "ssz" => "sz-sz"
+		"l·1l/l=l,1,3",   // Catalan: "l·l" => "l-l"
+		"o1n",            // standard pattern
+	} {
+		p, err := ParsePattern(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, p)
+	}
+	dict, err := LoadPatterns("non-standard", &slicePatternReader{entries: patterns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{word: "backen", want: []string{"bak", "ken"}},
+		{word: "Schiffahrt", want: []string{"Schiff", "fahrt"}},
+		{word: "SCHIFFAHRT", want: []string{"SCHIFF", "FAHRT"}},
+		{word: "asszony", want: []string{"asz", "szo", "ny"}},
+		{word: "col·lecció", want: []string{"col", "lecció"}},
+	}
+	for _, tt := range tests {
+		if got := dict.Hyphenate(tt.word); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("hyphenation mismatch for %q: got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+	breaks := dict.Breaks("backen")
+	want := []Break{{Rune: 3, Byte: 3, Level: 1, From: 2, To: 4, Pre: "k", Post: "k"}}
+	if !reflect.DeepEqual(breaks, want) {
+		t.Fatalf("unexpected breaks for backen: %+v", breaks)
+	}
+	if h := dict.HyphenationString("backen"); h != "bak-ken" {
+		t.Fatalf("backen should be bak-ken, is %s", h)
+	}
+	if h := dict.Encode("backen", SoftHyphen); h != "bac\u00ADken" {
+		t.Fatalf("soft hyphen encoding should keep the word intact, is %q", h)
+	}
+	if h := dict.Encode("backen", TeX); h != `ba\discretionary{k-}{k}{ck}en` {
+		t.Fatalf("unexpected TeX encoding %q", h)
+	}
+}
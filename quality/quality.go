@@ -0,0 +1,257 @@
+// Package quality measures the hyphenation quality of a dictionary against a
+// gold list of correctly hyphenated words, e.g. when upgrading pattern files
+// or adding exceptions.
+//
+// Every break of a gold word is either found by the dictionary (correct) or
+// not (missed); every break found by the dictionary which is not in the gold
+// list is bad. Precision is the share of correct breaks among the breaks
+// found, recall the share of correct breaks among the gold breaks.
+package quality
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// Counts holds the numbers of correct, missed and bad breaks.
+type Counts struct {
+	Correct int // breaks of the gold list found by the dictionary
+	Missed  int // breaks of the gold list not found by the dictionary
+	Bad     int // breaks found by the dictionary which are not in the gold list
+}
+
+// Precision returns Correct / (Correct + Bad), or 1 if no breaks are found.
+func (c Counts) Precision() float64 {
+	if c.Correct+c.Bad == 0 {
+		return 1
+	}
+	return float64(c.Correct) / float64(c.Correct+c.Bad)
+}
+
+// Recall returns Correct / (Correct + Missed), or 1 if there are no gold
+// breaks.
+func (c Counts) Recall() float64 {
+	if c.Correct+c.Missed == 0 {
+		return 1
+	}
+	return float64(c.Correct) / float64(c.Correct+c.Missed)
+}
+
+// Errors returns Missed + Bad.
+func (c Counts) Errors() int {
+	return c.Missed + c.Bad
+}
+
+func (c *Counts) add(other Counts) {
+	c.Correct += other.Correct
+	c.Missed += other.Missed
+	c.Bad += other.Bad
+}
+
+// Word is a word of the gold list with the breaks of the dictionary.
+type Word struct {
+	Counts
+	Word  string // as in the gold list, in Unicode normalization form NFC
+	Gold  []int  // breaks of the gold list, as rune offsets into Word
+	Found []int  // breaks found by the dictionary
+}
+
+// Hyphenation returns the word with hyphens inserted at breaks, e.g.
+// w.Hyphenation(w.Found).
+func (w Word) Hyphenation(breaks []int) string {
+	var sb strings.Builder
+	i := 0
+	for _, r := range w.Word {
+		if slices.Contains(breaks, i) {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(r)
+		i++
+	}
+	return sb.String()
+}
+
+// Report is the result of an evaluation.
+type Report struct {
+	Counts              // totals
+	Words      int      // number of words of the gold list
+	Ignored    int      // gold breaks which the hyphenmins of the dictionary forbid
+	ByLength   []Counts // by word length in runes
+	ByPosition []Counts // by position of the break, i.e. the number of runes before it
+	Errors     []Word   // words with missed or bad breaks, in the order of the gold list
+}
+
+// Evaluate hyphenates the words of gold with dict, using the dictionary's
+// options, and compares the breaks. gold may be a texexceptions.Reader or a
+// txt.ExceptionReader, for instance.
+//
+// Gold breaks which the hyphenmins of the dictionary forbid are not counted,
+// but reported as Report.Ignored, unless the dictionary breaks there anyway,
+// as exceptions may. Note that the exceptions of the dictionary
+// take part in the evaluation: a gold list which has been loaded as exceptions
+// is reproduced without errors.
+//
+// Gold words are compared in Unicode normalization form NFC, thus a
+// decomposed "fu\u0308r" counts as a word of three runes.
+func Evaluate(dict *hyphenate.Dictionary, gold hyphenate.ExceptionReader) (*Report, error) {
+	r := &Report{}
+	left, right := max(dict.Options.LeftMin, 1), max(dict.Options.RightMin, 1)
+	for {
+		word, positions, err := gold.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		word, positions = normalize(word, positions)
+		n := utf8.RuneCountInString(word)
+		w := Word{Word: word}
+		for _, brk := range dict.Breaks(word) {
+			w.Found = append(w.Found, brk.Rune)
+		}
+		for i, p := range positions {
+			if i == 0 || i >= n || p%2 == 0 {
+				continue
+			}
+			// exceptions of the dictionary may break within the hyphenmins
+			if (i < left || n-i < right) && !slices.Contains(w.Found, i) {
+				r.Ignored++
+				continue
+			}
+			w.Gold = append(w.Gold, i)
+		}
+		r.Words++
+		r.count(&w, n)
+		r.Counts.add(w.Counts)
+		if w.Errors() > 0 {
+			r.Errors = append(r.Errors, w)
+		}
+	}
+	return r, nil
+}
+
+// normalize converts a gold word to NFC and maps its positions, one per rune
+// of word, to the runes of the result. Positions within a normalization
+// segment, i.e. before a combining mark, are dropped.
+func normalize(word string, positions []int) (string, []int) {
+	if norm.NFC.IsNormalString(word) {
+		return word, positions
+	}
+	position := func(r int) int {
+		if r < len(positions) {
+			return positions[r]
+		}
+		return 0
+	}
+	var nfc []byte
+	var mapped []int
+	r := 0 // runes of word consumed
+	for i := 0; i < len(word); {
+		j := i + norm.NFC.NextBoundaryInString(word[i:], true)
+		if j <= i {
+			j = len(word)
+		}
+		first := len(nfc)
+		nfc = norm.NFC.AppendString(nfc, word[i:j])
+		mapped = append(mapped, position(r))
+		for range utf8.RuneCount(nfc[first:]) - 1 {
+			mapped = append(mapped, 0)
+		}
+		r += utf8.RuneCountInString(word[i:j])
+		i = j
+	}
+	return string(nfc), append(mapped, position(r))
+}
+
+// count compares the breaks of a word of n runes.
+func (r *Report) count(w *Word, n int) {
+	r.ByLength = grow(r.ByLength, n)
+	byPos := func(i int) *Counts {
+		r.ByPosition = grow(r.ByPosition, i)
+		return &r.ByPosition[i]
+	}
+	for _, i := range w.Gold {
+		if slices.Contains(w.Found, i) {
+			w.Correct++
+			byPos(i).Correct++
+		} else {
+			w.Missed++
+			byPos(i).Missed++
+		}
+	}
+	for _, i := range w.Found {
+		if !slices.Contains(w.Gold, i) {
+			w.Bad++
+			byPos(i).Bad++
+		}
+	}
+	r.ByLength[n].add(w.Counts)
+}
+
+// grow extends counts to hold index i.
+func grow(counts []Counts, i int) []Counts {
+	if i < len(counts) {
+		return counts
+	}
+	return append(counts, make([]Counts, i+1-len(counts))...)
+}
+
+// Worst returns the n words with the most errors, in descending order of
+// errors. Words with the same number of errors keep the order of the gold
+// list.
+func (r *Report) Worst(n int) []Word {
+	words := slices.Clone(r.Errors)
+	slices.SortStableFunc(words, func(a, b Word) int {
+		return b.Errors() - a.Errors()
+	})
+	return words[:min(n, len(words))]
+}
+
+// WriteText writes the report as text tables, followed by the worst
+// offenders (see Worst).
+func (r *Report) WriteText(w io.Writer, worst int) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "words\tbreaks\tcorrect\tmissed\tbad\tignored\tprecision\trecall\t\n")
+	fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%d\t%s\t", r.Words, r.Correct+r.Missed, r.Correct,
+		r.Missed, r.Bad, r.Ignored, percentages(r.Counts))
+	for _, table := range []struct {
+		title  string
+		counts []Counts
+	}{
+		{"length", r.ByLength},
+		{"position", r.ByPosition},
+	} {
+		fmt.Fprintf(tw, "\n\n%s\tcorrect\tmissed\tbad\tprecision\trecall\t", table.title)
+		for i, c := range table.counts {
+			if c != (Counts{}) {
+				fmt.Fprintf(tw, "\n%d\t%d\t%d\t%d\t%s\t", i, c.Correct, c.Missed, c.Bad, percentages(c))
+			}
+		}
+	}
+	fmt.Fprintln(tw)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if words := r.Worst(worst); len(words) > 0 {
+		fmt.Fprintf(w, "\nworst offenders:\n")
+		tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, word := range words {
+			fmt.Fprintf(tw, "%s\tfound %s\tmissed %d, bad %d\n", word.Hyphenation(word.Gold),
+				word.Hyphenation(word.Found), word.Missed, word.Bad)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+func percentages(c Counts) string {
+	return fmt.Sprintf("%.2f%%\t%.2f%%", 100*c.Precision(), 100*c.Recall())
+}
@@ -0,0 +1,107 @@
+package quality
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate/tex/texexceptions"
+	"github.com/npillmayer/hyphenate/tex/texpatterns"
+	"github.com/npillmayer/hyphenate/txt"
+)
+
+const gold = `ta-ble ab-ba ca-cao aca-de-my cab-by a-bout`
+
+func evaluate(t *testing.T) *Report {
+	t.Helper()
+	dict, err := texpatterns.LoadPatterns("test", strings.NewReader(`\patterns{1b 1c}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Evaluate(dict, txt.NewExceptionReader(strings.NewReader(gold)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestEvaluate(t *testing.T) {
+	r := evaluate(t)
+	if want := (Counts{Correct: 4, Missed: 2, Bad: 1}); r.Counts != want {
+		t.Fatalf("counts should be %+v, are %+v", want, r.Counts)
+	}
+	if r.Words != 6 || r.Ignored != 1 {
+		t.Fatalf("expected 6 words and 1 ignored break, have %d and %d", r.Words, r.Ignored)
+	}
+	if p, rc := r.Precision(), r.Recall(); p != 0.8 || rc != 4.0/6 {
+		t.Fatalf("precision and recall should be 0.8 and 0.67, are %.2f and %.2f", p, rc)
+	}
+	if want := (Counts{Correct: 3, Bad: 1}); r.ByLength[5] != want {
+		t.Fatalf("counts for length 5 should be %+v, are %+v", want, r.ByLength[5])
+	}
+	if want := (Counts{Correct: 1, Missed: 1}); r.ByPosition[3] != want {
+		t.Fatalf("counts for position 3 should be %+v, are %+v", want, r.ByPosition[3])
+	}
+	worst := r.Worst(5)
+	if len(worst) != 2 || worst[0].Word != "academy" || worst[1].Word != "cabby" {
+		t.Fatalf("worst offenders should be academy and cabby, are %v", worst)
+	}
+	if h := worst[1].Hyphenation(worst[1].Found); h != "ca-b-by" {
+		t.Fatalf("cabby should be found as ca-b-by, is %s", h)
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	dict, err := texpatterns.LoadPatterns("test", strings.NewReader(`\patterns{1b 1c}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	texexceptions.LoadExceptions(dict, strings.NewReader(`\hyphenation{aca-de-my}`))
+	r, err := Evaluate(dict, texexceptions.NewReader(strings.NewReader(`\hyphenation{aca-de-my cab-by}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Counts{Correct: 3, Bad: 1}); r.Counts != want {
+		t.Fatalf("counts should be %+v, are %+v", want, r.Counts)
+	}
+	// exceptions may break within the hyphenmins
+	texexceptions.LoadExceptions(dict, strings.NewReader(`\hyphenation{a-bout}`))
+	r, err = Evaluate(dict, txt.NewExceptionReader(strings.NewReader("a-bout")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Counts{Correct: 1}); r.Counts != want || r.Ignored != 0 {
+		t.Fatalf("counts should be %+v without ignored breaks, are %+v and %d", want, r.Counts, r.Ignored)
+	}
+}
+
+func TestNormalization(t *testing.T) {
+	dict, err := texpatterns.LoadPatterns("test", strings.NewReader(`\patterns{1b 1c}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Evaluate(dict, txt.NewExceptionReader(strings.NewReader("ta\u0301-ble")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Counts{Correct: 1}); r.Counts != want || len(r.ByLength) != 6 || r.ByLength[5] != want {
+		t.Fatalf("tá-ble should count as a correct break in a word of 5 runes, counts are %+v by length %+v",
+			r.Counts, r.ByLength)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := evaluate(t).WriteText(&buf, 1); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{"80.00%", "66.67%", "worst offenders", "aca-de-my  found academy  missed 2, bad 0"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("report should contain %q, is\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "cab-by  found") {
+		t.Fatalf("report should list a single offender, is\n%s", out)
+	}
+}
@@ -0,0 +1,150 @@
+package hyphenate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// ErrUnknownLanguage is returned by Registry.Load if neither a language nor
+// any of its fallbacks is registered.
+var ErrUnknownLanguage = errors.New("no hyphenation dictionary for language")
+
+// Language describes a language available from a Registry.
+type Language struct {
+	Tag      language.Tag // BCP 47 tag, e.g. de-1996 or en-US
+	Name     string       // English name of the language
+	License  string       // licence text of the patterns, if known
+	Metadata *Metadata    // metadata of the pattern source (title, version, licences), nil if unknown
+}
+
+// Registry maps BCP 47 language tags to dictionaries. Dictionaries are loaded
+// lazily on first use and shared afterwards. A Registry is safe for
+// concurrent use.
+//
+// Requests for languages which are not registered fall back to more general
+// tags: first along the CLDR parent chain (de-CH-1901 → de-CH → de,
+// en-AU → en-001 → en), finally to the base language. On every step an alias
+// (see Alias) takes precedence over a language registered under the step's
+// tag. With an alias de → de-1996, a request for de-CH therefore falls back
+// to de-1996, and to de only if de-1996 is not registered.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*registryEntry
+	aliases map[string]string
+}
+
+type registryEntry struct {
+	lang Language
+	load func() (*Dictionary, error)
+	mu   sync.Mutex  // serializes loading
+	dict *Dictionary // nil until loaded successfully
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*registryEntry),
+		aliases: make(map[string]string),
+	}
+}
+
+// Register makes a language available. load is called on the first request
+// for the language, and on later requests again until it succeeds.
+// Registering a tag twice or without a loader is an error.
+func (reg *Registry) Register(lang Language, load func() (*Dictionary, error)) error {
+	key := lang.Tag.String()
+	if load == nil {
+		return fmt.Errorf("language %s registered without loader", key)
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if _, dup := reg.entries[key]; dup {
+		return fmt.Errorf("language %s registered twice", key)
+	}
+	reg.entries[key] = &registryEntry{lang: lang, load: load}
+	return nil
+}
+
+// Alias redirects requests for tag from to tag to, e.g. de to de-1996 or
+// en-001 (English outside the US) to en-GB. Aliases to languages which are
+// not registered are ignored.
+func (reg *Registry) Alias(from, to language.Tag) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.aliases[from.String()] = to.String()
+}
+
+// Load returns the dictionary for a BCP 47 language tag, or for its closest
+// fallback (see Registry). A failed load is not cached: the next request for
+// the language tries again.
+func (reg *Registry) Load(tag string) (*Dictionary, error) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return nil, err
+	}
+	entry := reg.match(t)
+	if entry == nil {
+		return nil, fmt.Errorf("%w %s", ErrUnknownLanguage, tag)
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.dict == nil {
+		dict, err := entry.load()
+		if err != nil {
+			return nil, err
+		}
+		entry.dict = dict
+	}
+	return entry.dict, nil
+}
+
+// Match returns the language which serves requests for tag, without loading
+// its dictionary.
+func (reg *Registry) Match(tag language.Tag) (Language, bool) {
+	if entry := reg.match(tag); entry != nil {
+		return entry.lang, true
+	}
+	return Language{}, false
+}
+
+func (reg *Registry) match(tag language.Tag) *registryEntry {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	lookup := func(t language.Tag) *registryEntry {
+		key := t.String()
+		if alias, ok := reg.aliases[key]; ok {
+			if entry := reg.entries[alias]; entry != nil {
+				return entry
+			}
+		}
+		return reg.entries[key]
+	}
+	for t := tag; t != language.Und; t = t.Parent() {
+		if entry := lookup(t); entry != nil {
+			return entry
+		}
+	}
+	// some tags, e.g. sr-Latn, do not inherit from their base language
+	if base, conf := tag.Base(); conf != language.No {
+		return lookup(language.Make(base.String()))
+	}
+	return nil
+}
+
+// Languages returns all registered languages, ordered by tag.
+func (reg *Registry) Languages() []Language {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	langs := make([]Language, 0, len(reg.entries))
+	for _, entry := range reg.entries {
+		langs = append(langs, entry.lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		return langs[i].Tag.String() < langs[j].Tag.String()
+	})
+	return langs
+}
@@ -0,0 +1,112 @@
+package hyphenate_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex"
+)
+
+func TestRegistryFallback(t *testing.T) {
+	reg := hyphenate.NewRegistry()
+	loaders := make(map[string]*atomic.Int32)
+	for _, tag := range []string{"de", "de-1996", "de-CH-1901", "en-US", "en-GB", "sr"} {
+		calls := new(atomic.Int32)
+		loaders[tag] = calls
+		name := tag
+		err := reg.Register(hyphenate.Language{Tag: language.MustParse(tag)}, func() (*hyphenate.Dictionary, error) {
+			calls.Add(1)
+			return tex.LoadDictionary(name, strings.NewReader(`\patterns{a1b}`))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	unused := func() (*hyphenate.Dictionary, error) { return nil, nil }
+	if err := reg.Register(hyphenate.Language{Tag: language.German}, unused); err == nil {
+		t.Errorf("expected duplicate registration to fail")
+	}
+	if err := reg.Register(hyphenate.Language{Tag: language.French}, nil); err == nil {
+		t.Errorf("expected registration without loader to fail")
+	}
+	reg.Alias(language.German, language.MustParse("de-1996"))
+	reg.Alias(language.MustParse("en-001"), language.BritishEnglish)
+	reg.Alias(language.English, language.AmericanEnglish)
+	reg.Alias(language.French, language.MustParse("fr-FR")) // not registered
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"de-ch-1901", "de-CH-1901"},
+		{"de-CH", "de-1996"},
+		{"de", "de-1996"},
+		{"en-AU", "en-GB"},
+		{"en", "en-US"},
+		{"en-Latn-US", "en-US"},
+		{"sr-Latn", "sr"},
+	}
+	for _, tt := range tests {
+		lang, ok := reg.Match(language.MustParse(tt.tag))
+		if !ok || lang.Tag.String() != tt.want {
+			t.Errorf("expected %s to fall back to %s, got %v", tt.tag, tt.want, lang.Tag)
+		}
+	}
+	if _, ok := reg.Match(language.French); ok {
+		t.Errorf("expected no match for fr")
+	}
+	if _, err := reg.Load("fr"); !errors.Is(err, hyphenate.ErrUnknownLanguage) {
+		t.Errorf("expected ErrUnknownLanguage for fr, got %v", err)
+	}
+	var wg sync.WaitGroup
+	dicts := make([]*hyphenate.Dictionary, 8)
+	for i := range dicts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dicts[i], _ = reg.Load("de-AT")
+		}()
+	}
+	wg.Wait()
+	for _, dict := range dicts {
+		if dict == nil || dict != dicts[0] {
+			t.Fatalf("expected one shared dictionary, got %v", dicts)
+		}
+	}
+	if n := loaders["de-1996"].Load(); n != 1 {
+		t.Errorf("expected de-1996 to be loaded once, was loaded %d times", n)
+	}
+	if n := loaders["de"].Load(); n != 0 {
+		t.Errorf("expected de not to be loaded, was loaded %d times", n)
+	}
+	langs := reg.Languages()
+	if len(langs) != 6 || langs[0].Tag != language.German || langs[5].Tag.String() != "sr" {
+		t.Errorf("unexpected languages %v", langs)
+	}
+}
+
+func TestRegistryRetry(t *testing.T) {
+	reg := hyphenate.NewRegistry()
+	fail := true
+	err := reg.Register(hyphenate.Language{Tag: language.German}, func() (*hyphenate.Dictionary, error) {
+		if fail {
+			return nil, errors.New("temporary failure")
+		}
+		return tex.LoadDictionary("de", strings.NewReader(`\patterns{a1b}`))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reg.Load("de"); err == nil {
+		t.Fatalf("expected first load to fail")
+	}
+	fail = false
+	if dict, err := reg.Load("de"); err != nil || dict == nil {
+		t.Fatalf("expected second load to succeed, got error %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/npillmayer/hyphenate"
 	"github.com/npillmayer/hyphenate/tex/texexceptions"
 	"github.com/npillmayer/hyphenate/tex/texpatterns"
+	"github.com/npillmayer/hyphenate/tex/texscan"
 )
 
 // LoadDictionary loads a pattern dictionary and an exception list in TeX format.
@@ -26,15 +27,22 @@ import (
 //
 // This will load the patterns and exceptions temporarily into memory
 func LoadDictionary(name string, reader io.Reader) (*hyphenate.Dictionary, error) {
+	return LoadEncodedDictionary(name, reader, nil)
+}
+
+// LoadEncodedDictionary is like LoadDictionary, but for legacy TeX files in
+// an 8-bit encoding, e.g. texscan.T1 for dehyphn.tex. A nil encoding stands
+// for UTF-8.
+func LoadEncodedDictionary(name string, reader io.Reader, enc texscan.Encoding) (*hyphenate.Dictionary, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
-	texreader := texpatterns.NewPatternReader(bytes.NewReader(data))
+	texreader := texpatterns.NewEncodedPatternReader(bytes.NewReader(data), enc)
 	dict, err := hyphenate.LoadPatterns(name, texreader)
 	if err != nil {
 		return nil, err
 	}
-	err = dict.LoadExceptions(texexceptions.NewReader(bytes.NewReader(data)))
+	err = dict.LoadExceptions(texexceptions.NewEncodedReader(bytes.NewReader(data), enc))
 	return dict, err
 }
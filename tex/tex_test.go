@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex/texscan"
 )
 
 func mustLoadFixture(t *testing.T, file string) []byte {
@@ -26,9 +30,9 @@ func TestLoadDictionaryUSFixture(t *testing.T) {
 		word string
 		want string
 	}{
-		{word: "hello", want: "hel-lo"},
+		{word: "hello", want: "hello"},  // en-us sets right hyphenmin = 3
 		{word: "table", want: "ta-ble"}, // comes from TeX exceptions
-		{word: "computer", want: "com-put-er"},
+		{word: "computer", want: "com-puter"},
 	}
 	for _, tt := range tests {
 		if got := dict.HyphenationString(tt.word); got != tt.want {
@@ -37,6 +41,35 @@ func TestLoadDictionaryUSFixture(t *testing.T) {
 	}
 }
 
+func TestLoadDictionaryUSHyphenmins(t *testing.T) {
+	data := mustLoadFixture(t, "hyph-en-us.tex")
+	dict, err := LoadDictionary("hyph-en-us.tex", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Options.LeftMin != 2 || dict.Options.RightMin != 3 {
+		t.Fatalf("expected hyphenmins 2/3 from header, got %+v", dict.Options)
+	}
+	opts := dict.Options
+	opts.RightMin = 2
+	if got := strings.Join(dict.HyphenateWith("computer", opts), "-"); got != "com-put-er" {
+		t.Fatalf("with right hyphenmin 2 computer should be com-put-er, is %q", got)
+	}
+}
+
+func TestLoadDictionaryUSText(t *testing.T) {
+	data := mustLoadFixture(t, "hyph-en-us.tex")
+	dict, err := LoadDictionary("hyph-en-us.tex", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := `"Algorithms," she said, "need 2 tables."`
+	want := `"Al-go-rithms," she said, "need 2 ta-bles."`
+	if got := dict.HyphenateText(text, hyphenate.Hyphen); got != want {
+		t.Fatalf("text mismatch: got %q, want %q", got, want)
+	}
+}
+
 func TestLoadDictionaryGermanFixtureUmlauts(t *testing.T) {
 	data := mustLoadFixture(t, "hyph-de-1996.tex")
 	dict, err := LoadDictionary("hyph-de-1996.tex", bytes.NewReader(data))
@@ -56,3 +89,63 @@ func TestLoadDictionaryGermanFixtureUmlauts(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadDictionaryGermanFixtureCasing(t *testing.T) {
+	data := mustLoadFixture(t, "hyph-de-1996.tex")
+	dict, err := LoadDictionary("hyph-de-1996.tex", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "fürsorge", want: "für-sor-ge"},
+		{word: "Fürsorge", want: "Für-sor-ge"},
+		{word: "FÜRSORGE", want: "FÜR-SOR-GE"},
+		{word: "Öffentlichkeit", want: "Öf-fent-lich-keit"},
+	}
+	for _, tt := range tests {
+		if got := dict.HyphenationString(tt.word); got != tt.want {
+			t.Fatalf("hyphenation mismatch for %q: got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+	opts := dict.Options
+	opts.SkipAllCaps = true
+	if got := dict.HyphenateWith("FÜRSORGE", opts); len(got) != 1 {
+		t.Fatalf("all-caps word should not be hyphenated, got %v", got)
+	}
+	if got := dict.HyphenateWith("Fürsorge", opts); len(got) != 3 {
+		t.Fatalf("title-case word should be hyphenated, got %v", got)
+	}
+}
+
+func TestLoadEncodedDictionary(t *testing.T) {
+	// a legacy file in T1 encoding: \xe4 is "ä", \xff is "ß"
+	source := "\\lccode`\\'=`\\'\n" +
+		"\\patterns{ \xe41f 1^^ff '1l }\n" +
+		"\\hyphenation{ st\xe4d-te }"
+	dict, err := LoadEncodedDictionary("t1", strings.NewReader(source), texscan.T1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := dict.Options
+	opts.LeftMin, opts.RightMin = 1, 1
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "käfer", want: "kä-fer"},
+		{word: "Größe", want: "Grö-ße"},
+		{word: "Städte", want: "Städ-te"}, // from exceptions
+	}
+	for _, tt := range tests {
+		if got := strings.Join(dict.HyphenateWith(tt.word, opts), "-"); got != tt.want {
+			t.Errorf("hyphenation mismatch for %q: got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+	th := hyphenate.TextHyphenator{Dictionary: dict, Encoder: hyphenate.Hyphen, Options: &opts}
+	if got := th.String("all'lago"); got != "all'-lago" { // ' is a letter
+		t.Errorf("text hyphenation with declared letters: got %q", got)
+	}
+}
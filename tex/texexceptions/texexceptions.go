@@ -1,19 +1,15 @@
 package texexceptions
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
 	"io"
-	"strings"
 
 	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex/texscan"
 )
 
 // Reader streams hyphenation exceptions from TeX \hyphenation{...} blocks.
 type Reader struct {
-	scanner *bufio.Scanner
-	inBlock bool
+	blocks *texscan.BlockReader
 }
 
 // LoadExceptions parses TeX exception data from reader and adds all
@@ -22,67 +18,35 @@ func LoadExceptions(dict *hyphenate.Dictionary, reader io.Reader) {
 	dict.LoadExceptions(NewReader(reader))
 }
 
+// LoadEncodedExceptions is like LoadExceptions, but for legacy TeX files in
+// an 8-bit encoding.
+func LoadEncodedExceptions(dict *hyphenate.Dictionary, reader io.Reader, enc texscan.Encoding) {
+	dict.LoadExceptions(NewEncodedReader(reader, enc))
+}
+
+// NewReader creates a reader for the words of all \hyphenation{...} blocks of
+// a TeX file in UTF-8. Words are separated by white space and may be followed
+// by comments on the same line.
 func NewReader(reader io.Reader) *Reader {
+	return NewEncodedReader(reader, nil)
+}
+
+// NewEncodedReader creates a reader for the words of all \hyphenation{...}
+// blocks of a TeX file in encoding enc, which is nil for UTF-8. Characters
+// may be given in TeX's ^^ notation, which refers to the encoding as well.
+func NewEncodedReader(reader io.Reader, enc texscan.Encoding) *Reader {
 	return &Reader{
-		scanner: bufio.NewScanner(reader),
+		blocks: texscan.NewEncodedBlockReader(reader, "hyphenation", enc),
 	}
 }
 
 // Next returns the next exception as (word, positions).
 // It returns io.EOF when exhausted.
 func (r *Reader) Next() (string, []int, error) {
-	fmt.Println("HELLO")
-	for r.scanner.Scan() {
-		line := r.scanner.Text()
-		fmt.Println(line)
-		if strings.HasPrefix(line, "%") || line == "" {
-			continue
-		}
-		if strings.HasPrefix(line, "\\patterns{") {
-			skipTeXBlock(r.scanner)
-			continue
-		}
-		if strings.HasPrefix(line, "\\hyphenation{") {
-			r.inBlock = true
-			fmt.Printf("======== in block ============")
-			continue
-		}
-		if strings.HasPrefix(line, "}") {
-			if r.inBlock {
-				fmt.Printf("======== eof block ===========")
-				return "", nil, io.EOF
-			}
-			continue
-		}
-		positions := make([]int, 0, len(line))
-		wasHyphen := false
-		for _, ch := range line {
-			if ch == '-' {
-				positions = append(positions, 1)
-				wasHyphen = true
-			} else if wasHyphen {
-				wasHyphen = false
-			} else {
-				positions = append(positions, 0)
-			}
-		}
-		word := strings.ReplaceAll(line, "-", "")
-		return word, positions, nil
-	}
-	if err := r.scanner.Err(); err != nil {
+	tok, err := r.blocks.Next()
+	if err != nil {
 		return "", nil, err
 	}
-	if r.inBlock {
-		return "", nil, errors.New("unexpected end of file (unclosed \\hyphenation block)")
-	}
-	return "", nil, io.EOF
-}
-
-func skipTeXBlock(scanner *bufio.Scanner) {
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "}") {
-			return
-		}
-	}
+	word, positions := hyphenate.ParseException(tok.Text)
+	return word, positions, nil
 }
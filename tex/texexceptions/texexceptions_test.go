@@ -100,3 +100,22 @@ type emptyPatternReader struct{}
 func (r emptyPatternReader) Next() (sequence []rune, weights []int, err error) {
 	return nil, nil, io.EOF
 }
+
+func TestReaderTokens(t *testing.T) {
+	r := NewReader(strings.NewReader(`\patterns{a1b}
+\hyphenation{ta-ble  as-so-ciate % comment
+pre-sent}\hyphenation{ob-li-ga-tory}`))
+	var words []string
+	for {
+		word, _, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		words = append(words, word)
+	}
+	if !reflect.DeepEqual(words, []string{"table", "associate", "present", "obligatory"}) {
+		t.Fatalf("unexpected words %v", words)
+	}
+}
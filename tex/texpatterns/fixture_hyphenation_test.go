@@ -48,9 +48,9 @@ func TestUSPatternsFixture(t *testing.T) {
 		word string
 		want string
 	}{
-		{word: "hello", want: "hel-lo"},
+		{word: "hello", want: "hello"}, // en-us sets right hyphenmin = 3
 		{word: "table", want: "ta-ble"},
-		{word: "computer", want: "com-put-er"},
+		{word: "computer", want: "com-puter"},
 		{word: "algorithm", want: "al-go-rithm"},
 		{word: "concatenation", want: "con-cate-na-tion"},
 		{word: "quick", want: "quick"},
@@ -0,0 +1,286 @@
+package texpatterns
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// ParseMetadata parses the YAML-like header of hyph-utf8 pattern files, i.e.
+//
+//	% title: German Hyphenation Patterns (Reformed Orthography, 2006)
+//	% version: 2024-02-28
+//	% authors:
+//	%   -
+//	%     name:    Deutschsprachige Trennmustermannschaft
+//	%     contact: trennmuster@dante.de
+//	% licence:
+//	%     name: MIT
+//	%     text: >
+//	%           Permission is hereby granted, ...
+//	% ==========
+//
+// Comment characters are optional, so ParseMetadata reads hyph-utf8 .lic.txt
+// files as well. Parsing stops at a line of '=' characters or at the first
+// line which is neither a comment nor empty. Unknown keys are ignored and
+// malformed lines are skipped; only errors of reader are reported. If there
+// is no header, the result is nil.
+func ParseMetadata(reader io.Reader) (*hyphenate.Metadata, error) {
+	var h header
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() && h.add(scanner.Text()) {
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return h.metadata(), nil
+}
+
+// header collects the lines of a hyph-utf8 header.
+type header struct {
+	lines   []headerLine
+	comment bool // lines are TeX comments
+	done    bool
+}
+
+type headerLine struct {
+	indent int
+	text   string // without indentation; empty for blank lines
+}
+
+// add appends a line to the header and reports whether the header may
+// continue.
+func (h *header) add(line string) bool {
+	if h.done {
+		return false
+	}
+	if len(h.lines) == 0 && !h.comment {
+		h.comment = strings.HasPrefix(line, "%")
+	}
+	if h.comment {
+		var ok bool
+		if line, ok = strings.CutPrefix(line, "%"); !ok && strings.TrimSpace(line) != "" {
+			h.done = true
+			return false
+		}
+	}
+	text := strings.TrimLeft(line, " ")
+	if strings.HasPrefix(text, "===") {
+		h.done = true
+		return false
+	}
+	h.lines = append(h.lines, headerLine{indent: len(line) - len(text), text: strings.TrimRight(text, " \t")})
+	return true
+}
+
+// metadata interprets the header lines. It returns nil if the header does not
+// contain any keys.
+func (h *header) metadata() *hyphenate.Metadata {
+	root := parseNode(h.lines, new(int), 0)
+	if root.fields == nil {
+		return nil
+	}
+	meta := &hyphenate.Metadata{
+		Title:     root.get("title").value,
+		Version:   root.get("version").value,
+		Notice:    root.get("notice").value,
+		Copyright: root.get("copyright").value,
+		Source:    root.get("source").value,
+		Language:  root.get("language", "name").value,
+		Tag:       root.get("language", "tag").value,
+	}
+	for _, a := range root.get("authors").list() {
+		if a.fields == nil {
+			meta.Authors = append(meta.Authors, hyphenate.Author{Name: a.value})
+			continue
+		}
+		meta.Authors = append(meta.Authors, hyphenate.Author{
+			Name:    a.get("name").value,
+			Contact: a.get("contact").value,
+		})
+	}
+	for _, l := range root.get("licence").list() {
+		meta.Licenses = append(meta.Licenses, hyphenate.License{
+			Name: l.get("name").value,
+			URL:  l.get("url").value,
+			Text: l.get("text").value,
+		})
+	}
+	meta.GenerationMins = root.get("hyphenmins", "generation").hyphenmins()
+	meta.TypesettingMins = root.get("hyphenmins", "typesetting").hyphenmins()
+	return meta
+}
+
+// node is a node of the YAML subset used by hyph-utf8 headers: a scalar, a
+// mapping or a list.
+type node struct {
+	value  string
+	fields map[string]*node
+	items  []*node
+}
+
+// get returns the node at a path of keys, or an empty node.
+func (n *node) get(path ...string) *node {
+	for _, key := range path {
+		if n == nil || n.fields[key] == nil {
+			return &node{}
+		}
+		n = n.fields[key]
+	}
+	if n == nil {
+		return &node{}
+	}
+	return n
+}
+
+// list returns the items of a list node. Other non-empty nodes are treated as
+// a list of one.
+func (n *node) list() []*node {
+	if n.items != nil {
+		return n.items
+	}
+	if n.value == "" && n.fields == nil {
+		return nil
+	}
+	return []*node{n}
+}
+
+func (n *node) hyphenmins() hyphenate.Hyphenmins {
+	left, _ := strconv.Atoi(n.get("left").value)
+	right, _ := strconv.Atoi(n.get("right").value)
+	return hyphenate.Hyphenmins{Left: left, Right: right}
+}
+
+var keyPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_-]*):(?:\s+(.*))?$`)
+
+// parseNode parses the node starting at line *pos, with lines indented by at
+// least minIndent.
+func parseNode(lines []headerLine, pos *int, minIndent int) *node {
+	skipBlank(lines, pos)
+	if *pos >= len(lines) || lines[*pos].indent < minIndent {
+		return &node{}
+	}
+	indent := lines[*pos].indent
+	n := &node{}
+	if isListItem(lines[*pos].text) {
+		for skipBlank(lines, pos); *pos < len(lines); skipBlank(lines, pos) {
+			line := lines[*pos]
+			if line.indent != indent || !isListItem(line.text) {
+				break
+			}
+			rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+			if rest == "" {
+				*pos++
+			} else { // item starts on the line of the dash
+				lines[*pos] = headerLine{indent: indent + len(line.text) - len(rest), text: rest}
+			}
+			n.items = append(n.items, parseNode(lines, pos, indent+1))
+		}
+		return n
+	}
+	if keyPattern.FindStringSubmatch(lines[*pos].text) == nil {
+		n.value = parseScalar(lines, pos, indent-1, "")
+		return n
+	}
+	n.fields = make(map[string]*node)
+	for skipBlank(lines, pos); *pos < len(lines); skipBlank(lines, pos) {
+		line := lines[*pos]
+		if line.indent < indent {
+			break
+		}
+		m := keyPattern.FindStringSubmatch(line.text)
+		if line.indent > indent || m == nil {
+			*pos++ // stray continuation line
+			continue
+		}
+		*pos++
+		key, value := m[1], m[2]
+		switch {
+		case value == "":
+			n.fields[key] = parseNode(lines, pos, indent+1)
+		case value == ">" || value == "|" || value == ">-" || value == "|-":
+			n.fields[key] = &node{value: parseBlockScalar(lines, pos, indent, value[0] == '|')}
+		default:
+			n.fields[key] = &node{value: parseScalar(lines, pos, indent, value)}
+		}
+	}
+	return n
+}
+
+// parseScalar parses a plain or quoted scalar, which may continue on lines
+// indented by more than indent. Continuation lines are joined by spaces.
+func parseScalar(lines []headerLine, pos *int, indent int, first string) string {
+	words := []string{}
+	if first != "" {
+		words = append(words, first)
+	}
+	for *pos < len(lines) && lines[*pos].indent > indent && lines[*pos].text != "" {
+		if first != "" && keyPattern.MatchString(lines[*pos].text) {
+			break // nested mapping after a value, i.e. malformed
+		}
+		words = append(words, lines[*pos].text)
+		*pos++
+	}
+	return unquote(strings.Join(words, " "))
+}
+
+// parseBlockScalar parses a folded ('>') or literal ('|') block scalar,
+// consisting of the lines indented by more than indent.
+func parseBlockScalar(lines []headerLine, pos *int, indent int, literal bool) string {
+	var sb strings.Builder
+	blanks := 0
+	for *pos < len(lines) {
+		line := lines[*pos]
+		if line.text == "" {
+			blanks++
+			*pos++
+			continue
+		}
+		if line.indent <= indent {
+			break
+		}
+		if sb.Len() > 0 {
+			switch {
+			case literal:
+				sb.WriteString(strings.Repeat("\n", blanks+1))
+			case blanks > 0:
+				sb.WriteString(strings.Repeat("\n", blanks))
+			default:
+				sb.WriteByte(' ')
+			}
+		}
+		blanks = 0
+		sb.WriteString(line.text)
+		*pos++
+	}
+	if blanks > 0 { // trailing blank lines belong to the following node
+		*pos -= blanks
+	}
+	return sb.String()
+}
+
+func skipBlank(lines []headerLine, pos *int) {
+	for *pos < len(lines) && lines[*pos].text == "" {
+		*pos++
+	}
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if u, err := strconv.Unquote(s); err == nil {
+				return u
+			}
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
@@ -1,27 +1,29 @@
 package texpatterns
 
 import (
-	"bufio"
-	"errors"
+	"fmt"
 	"io"
-	"strconv"
 	"strings"
-	"unicode"
 
 	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex/texscan"
 )
 
 // PatternReader streams Liang patterns from TeX-style source files.
 type PatternReader struct {
-	scanner    *bufio.Scanner
-	identifier string
-	sequence   []rune
-	weights    []int
+	blocks      *texscan.BlockReader
+	identifier  string
+	header      header                 // hyph-utf8 comment header
+	headerLine  int                    // line of the most recent header comment
+	letters     map[rune]rune          // from \lccode assignments
+	metadata    *hyphenate.Metadata    // parsed from header
+	replacement *hyphenate.Replacement // of the most recent non-standard pattern
+	line        int                    // of the most recent pattern
 }
 
 // LoadPatterns parses TeX pattern data and returns a ready-to-use dictionary.
 //
-// Patterns are enclosed in between
+// Patterns are enclosed in one or more blocks
 //
 //	\patterns{ % some comment
 //	 ...
@@ -34,11 +36,15 @@ type PatternReader struct {
 //	 ...
 //	}
 //
-// Odd numbers stand for possible discretionary breakpoints, even numbers forbid
-// hyphenation. Digits belong to the character immediately after them, i.e.,
+// Patterns are separated by white space, and comments may follow them on the
+// same line. Odd numbers stand for possible discretionary breakpoints, even
+// numbers forbid hyphenation. Digits belong to the character immediately after them, i.e.,
 //
 //	"a5ban" => (a)(5b)(a)(n) => positions["aban"] = [0,5,0,0].
 //
+// Non-standard patterns in libhyphen syntax (e.g. "c1k/k=k,1,2") are
+// supported as well, see hyphenate.ParsePattern.
+//
 // The loader parses TeX input into a streaming PatternReader and compiles
 // patterns incrementally.
 //
@@ -48,91 +54,117 @@ func LoadPatterns(name string, reader io.Reader) (*hyphenate.Dictionary, error)
 	return hyphenate.LoadPatterns(name, r)
 }
 
+// LoadEncodedPatterns is like LoadPatterns, but for legacy TeX files in an
+// 8-bit encoding, e.g. texscan.T1 for dehyphn.tex.
+func LoadEncodedPatterns(name string, reader io.Reader, enc texscan.Encoding) (*hyphenate.Dictionary, error) {
+	r := NewEncodedPatternReader(reader, enc)
+	return hyphenate.LoadPatterns(name, r)
+}
+
+// NewPatternReader creates a reader for the patterns of all \patterns{...}
+// blocks of a TeX file in UTF-8.
 func NewPatternReader(reader io.Reader) *PatternReader {
-	return &PatternReader{
-		scanner:  bufio.NewScanner(reader),
-		sequence: make([]rune, 0, 32),
-		weights:  make([]int, 0, 32),
+	return NewEncodedPatternReader(reader, nil)
+}
+
+// NewEncodedPatternReader creates a reader for the patterns of all
+// \patterns{...} blocks of a TeX file in encoding enc, which is nil for UTF-8.
+//
+// Characters may be given in TeX's ^^ notation (e.g. ^^e4), which refers to
+// the encoding as well. Assignments \lccode`\^^e4=`\^^e4 declare additional
+// letters (see hyphenate.LetterReader).
+func NewEncodedPatternReader(reader io.Reader, enc texscan.Encoding) *PatternReader {
+	r := &PatternReader{
+		blocks: texscan.NewEncodedBlockReader(reader, "patterns", enc),
 	}
+	r.blocks.Comment = r.comment
+	r.blocks.Message = func(text string) { r.identifier = text }
+	r.blocks.Letter = r.letter
+	return r
 }
 
 func (r *PatternReader) Identifier() string {
 	return r.identifier
 }
 
+// Hyphenmins returns the typesetting hyphenmins found in the comment header
+// of hyph-utf8 files, i.e.
+//
+//	% hyphenmins:
+//	%     typesetting:
+//	%         left: 2
+//	%         right: 3
+//
+// Values are 0 if the source does not specify them. The header is read as part
+// of the pattern stream, so Hyphenmins should be called after Next has
+// returned io.EOF.
+func (r *PatternReader) Hyphenmins() (left, right int) {
+	if meta := r.Metadata(); meta != nil {
+		return meta.TypesettingMins.Left, meta.TypesettingMins.Right
+	}
+	return 0, 0
+}
+
+// Metadata returns the metadata found in the comment header of hyph-utf8
+// files (see ParseMetadata), or nil if the source has no such header. Like
+// Hyphenmins, it should be called after Next has returned io.EOF.
+func (r *PatternReader) Metadata() *hyphenate.Metadata {
+	if r.metadata == nil && len(r.header.lines) > 0 {
+		r.metadata = r.header.metadata()
+	}
+	return r.metadata
+}
+
+// Letters returns the letters declared by \lccode assignments, with their
+// lowercase forms. Like Hyphenmins, it should be called after Next has
+// returned io.EOF.
+func (r *PatternReader) Letters() map[rune]rune {
+	return r.letters
+}
+
+func (r *PatternReader) letter(letter, lower rune) {
+	if r.letters == nil {
+		r.letters = make(map[rune]rune)
+	}
+	r.letters[letter] = lower
+}
+
+// Replacement returns the replacement of the pattern most recently returned
+// by Next if it is a non-standard pattern, and nil otherwise.
+func (r *PatternReader) Replacement() *hyphenate.Replacement {
+	return r.replacement
+}
+
 // Next returns the next pattern as (sequence, weights).
-// It returns io.EOF when exhausted.
-// The returned slices are reused by subsequent calls.
+// It returns io.EOF when exhausted. Malformed patterns are reported as errors
+// naming their line.
 func (r *PatternReader) Next() ([]rune, []int, error) {
-	var err error
-	inPatterns := false
-	for r.scanner.Scan() {
-		line := r.scanner.Text()
-		if strings.HasPrefix(line, "%     message: ") {
-			r.identifier = line[15:]
-			continue
-		}
-		if strings.HasPrefix(line, "\\message{") {
-			r.identifier = line[9 : len(line)-1]
-			continue
-		}
-		if strings.HasPrefix(line, "\\hyphenation{") {
-			skipTeXBlock(r.scanner)
-			continue
-		}
-		if strings.HasPrefix(line, "%") || line == "" {
-			continue
-		}
-		if strings.HasPrefix(line, "\\patterns{") {
-			inPatterns = true
-			continue
-		}
-		if strings.HasPrefix(line, "}") {
-			if inPatterns { // closing of patterns block
-				return nil, nil, io.EOF // do not read further
-			}
-			continue
-		}
-		r.decodePatternLine(line)
-		if len(r.sequence) == 0 {
-			continue
-		}
-		return r.sequence, r.weights, nil
-	}
-	if err = r.scanner.Err(); err != nil {
+	tok, err := r.blocks.Next()
+	if err != nil {
 		return nil, nil, err
 	}
-	if inPatterns {
-		err = errors.New("unexpected end of file (unclosed \\patterns block)")
+	r.header.done = true
+	p, err := hyphenate.ParsePattern(tok.Text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", tok.Line, err)
 	}
-	return nil, nil, io.EOF
+	r.replacement, r.line = p.Replacement, tok.Line
+	return p.Sequence, p.Weights, nil
 }
 
-func (r *PatternReader) decodePatternLine(line string) {
-	r.sequence = r.sequence[:0]
-	r.weights = r.weights[:0]
-	wasDigit := false
-	for _, ch := range line {
-		if unicode.IsDigit(ch) {
-			d, _ := strconv.Atoi(string(ch))
-			r.weights = append(r.weights, d)
-			wasDigit = true
-			continue
-		}
-		r.sequence = append(r.sequence, ch)
-		if wasDigit {
-			wasDigit = false
-		} else {
-			r.weights = append(r.weights, 0)
-		}
-	}
+// Line returns the line of the pattern most recently returned by Next.
+func (r *PatternReader) Line() int {
+	return r.line
 }
 
-func skipTeXBlock(scanner *bufio.Scanner) {
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "}") {
-			return
-		}
+// comment collects the comment lines at the start of the input, which form
+// the hyph-utf8 header.
+func (r *PatternReader) comment(tok texscan.Token) {
+	if text, ok := strings.CutPrefix(tok.Text, "     message: "); ok {
+		r.identifier = text
+	}
+	if tok.Line != r.headerLine+1 || !r.header.add("%"+tok.Text) {
+		r.header.done = true
 	}
+	r.headerLine = tok.Line
 }
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/npillmayer/hyphenate"
 )
 
 func TestPatternReader(t *testing.T) {
@@ -31,3 +33,150 @@ fü1r
 		t.Fatalf("expected io.EOF, got %v", err)
 	}
 }
+
+func TestPatternReaderHyphenmins(t *testing.T) {
+	src := strings.NewReader(`% hyphenmins:
+%     generation:
+%         left:  1
+%         right: 1
+%     typesetting:
+%         left:  2
+%         right: 3
+% ==========
+\patterns{
+a1b
+}`)
+	r := NewPatternReader(src)
+	for {
+		if _, _, err := r.Next(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+	}
+	if left, right := r.Hyphenmins(); left != 2 || right != 3 {
+		t.Fatalf("hyphenmins mismatch: got %d/%d, want 2/3", left, right)
+	}
+}
+
+func TestPatternReaderNonStandard(t *testing.T) {
+	dict, err := LoadPatterns("non-standard", strings.NewReader(`\patterns{
+c1k/k=k,1,2
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := dict.HyphenationString("Zucker"); h != "Zuk-ker" {
+		t.Fatalf("Zucker should be Zuk-ker, is %s", h)
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	src := strings.NewReader(`% title: Test Patterns
+% notice: first line
+%     second line
+% language:
+%     name: Testish
+%     tag:  tt
+% authors:
+%   - name: Alice
+%     contact: alice@example.org
+%   - Bob
+% licence:
+%   -
+%     name: MIT
+%     text: >
+%         Permission is
+%         granted.
+%
+%         Really.
+%   -
+%     name: LPPL
+%     url: 'https://www.latex-project.org/lppl/'
+% hyphenmins:
+%     generation:
+%         left: 1
+%         right: 2
+%     typesetting:
+%         left: 2
+%         right: 3
+% ====
+% title: not part of the header
+\patterns{
+a1b
+}`)
+	meta, err := ParseMetadata(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &hyphenate.Metadata{
+		Title:    "Test Patterns",
+		Notice:   "first line second line",
+		Language: "Testish",
+		Tag:      "tt",
+		Authors: []hyphenate.Author{
+			{Name: "Alice", Contact: "alice@example.org"},
+			{Name: "Bob"},
+		},
+		Licenses: []hyphenate.License{
+			{Name: "MIT", Text: "Permission is granted.\nReally."},
+			{Name: "LPPL", URL: "https://www.latex-project.org/lppl/"},
+		},
+		GenerationMins:  hyphenate.Hyphenmins{Left: 1, Right: 2},
+		TypesettingMins: hyphenate.Hyphenmins{Left: 2, Right: 3},
+	}
+	if !reflect.DeepEqual(meta, want) {
+		t.Fatalf("metadata mismatch:\n got %+v\nwant %+v", meta, want)
+	}
+}
+
+func TestPatternReaderMetadata(t *testing.T) {
+	dict, err := LoadPatterns("no-header", strings.NewReader(`\patterns{
+a1b
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Metadata != nil {
+		t.Fatalf("expected no metadata, got %+v", dict.Metadata)
+	}
+	dict, err = LoadPatterns("header", strings.NewReader(`% title: Test
+% version: 1.0
+% ==
+% comment: not metadata
+\patterns{
+a1b
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Metadata == nil || dict.Metadata.Title != "Test" || dict.Metadata.Version != "1.0" {
+		t.Fatalf("unexpected metadata %+v", dict.Metadata)
+	}
+}
+
+func TestPatternReaderTokens(t *testing.T) {
+	dict, err := LoadPatterns("tokens", strings.NewReader(`\patterns{ 1ba 1be % inline comment
+1bi}
+\hyphenation{ta-ble}
+\patterns{1bo
+1bu}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"aabax", "aabex", "aabix", "aabox", "aabux"} {
+		if h := dict.HyphenationString(word); h != word[:2]+"-"+word[2:] {
+			t.Errorf("%s should be hyphenated after the second letter, is %s", word, h)
+		}
+	}
+	if h := dict.HyphenationString("table"); h != "table" {
+		t.Errorf("exceptions should not be loaded, but table is %s", h)
+	}
+}
+
+func TestPatternReaderMalformed(t *testing.T) {
+	_, err := LoadPatterns("malformed", strings.NewReader("\\patterns{1ba\nc1k/k=k,x,2 1be}"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("malformed pattern should be reported for line 2, error is %v", err)
+	}
+}
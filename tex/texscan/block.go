@@ -0,0 +1,145 @@
+package texscan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// BlockReader yields the words within all blocks \command{...} of a TeX
+// file, e.g. the patterns of all \patterns blocks. Words outside these blocks,
+// including the contents of other blocks, are skipped.
+type BlockReader struct {
+	// Comment, if not nil, is called for every comment.
+	Comment func(tok Token)
+	// Message, if not nil, is called with the text of every \message{...}.
+	Message func(text string)
+	// Letter, if not nil, is called for every assignment \lccode letter=lower
+	// outside of blocks. lower is 0 for characters which are not letters.
+	Letter func(letter, lower rune)
+
+	scanner *Scanner
+	command string
+	pending bool // command seen, expecting '{'
+	depth   int  // nesting of braces within a block, 0 outside of blocks
+	line    int  // start of the current block
+	done    bool
+}
+
+// NewBlockReader creates a reader for the blocks of command (without
+// backslash), e.g. "patterns". The input has to be UTF-8.
+func NewBlockReader(reader io.Reader, command string) *BlockReader {
+	return NewEncodedBlockReader(reader, command, nil)
+}
+
+// NewEncodedBlockReader creates a reader for the blocks of command, reading
+// input in encoding enc. Use a nil encoding for UTF-8.
+func NewEncodedBlockReader(reader io.Reader, command string, enc Encoding) *BlockReader {
+	return &BlockReader{scanner: NewEncodedScanner(reader, enc), command: command}
+}
+
+// Next returns the next word within a block. It returns io.EOF at the end of
+// the input or at \endinput.
+func (b *BlockReader) Next() (Token, error) {
+	for !b.done {
+		tok, err := b.scanner.Next()
+		if err == io.EOF {
+			b.done = true
+			if b.depth > 0 {
+				return Token{}, fmt.Errorf("unexpected end of file (unclosed \\%s block from line %d)", b.command, b.line)
+			}
+			break
+		} else if err != nil {
+			return Token{}, err
+		}
+		if b.pending && tok.Kind != BeginGroup && tok.Kind != Comment {
+			return Token{}, fmt.Errorf("line %d: \\%s not followed by '{'", tok.Line, b.command)
+		}
+		switch tok.Kind {
+		case Comment:
+			if b.Comment != nil {
+				b.Comment(tok)
+			}
+		case Command:
+			switch {
+			case b.depth > 0:
+				// ignore commands within blocks
+			case tok.Text == b.command:
+				b.pending = true
+			case tok.Text == "message":
+				if err = b.message(); err != nil {
+					return Token{}, err
+				}
+			case tok.Text == "lccode":
+				if err = b.lccode(); err != nil {
+					return Token{}, err
+				}
+			case tok.Text == "endinput":
+				b.done = true
+			}
+		case BeginGroup:
+			if b.pending {
+				b.pending, b.line = false, tok.Line
+			} else if b.depth == 0 {
+				continue // a group outside of blocks
+			}
+			b.depth++
+		case EndGroup:
+			if b.depth > 0 {
+				b.depth--
+			}
+		case Word:
+			if b.depth > 0 {
+				return tok, nil
+			}
+		}
+	}
+	return Token{}, io.EOF
+}
+
+// message reads the argument of \message and passes it to b.Message.
+func (b *BlockReader) message() error {
+	var words []string
+	depth := 0
+	for {
+		tok, err := b.scanner.Next()
+		if err == io.EOF {
+			return fmt.Errorf("unexpected end of file (unclosed \\message)")
+		} else if err != nil {
+			return err
+		}
+		switch tok.Kind {
+		case BeginGroup:
+			depth++
+		case EndGroup:
+			depth--
+		case Word:
+			words = append(words, tok.Text)
+		case Command:
+			words = append(words, "\\"+tok.Text)
+		}
+		if depth <= 0 && tok.Kind != Comment {
+			break
+		}
+	}
+	if b.Message != nil {
+		b.Message(strings.Join(words, " "))
+	}
+	return nil
+}
+
+// lccode reads the arguments of \lccode and passes them to b.Letter.
+func (b *BlockReader) lccode() error {
+	letter, err := b.scanner.CharCode()
+	if err != nil {
+		return fmt.Errorf("malformed \\lccode: %w", err)
+	}
+	lower, err := b.scanner.CharCode()
+	if err != nil {
+		return fmt.Errorf("malformed \\lccode: %w", err)
+	}
+	if b.Letter != nil {
+		b.Letter(letter, lower)
+	}
+	return nil
+}
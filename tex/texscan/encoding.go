@@ -0,0 +1,66 @@
+package texscan
+
+import (
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+)
+
+// Encoding maps the bytes of a legacy 8-bit TeX file to Unicode. A nil
+// Encoding stands for UTF-8, the encoding of hyph-utf8 files.
+//
+// Every *charmap.Charmap of package golang.org/x/text/encoding/charmap is an
+// Encoding.
+type Encoding interface {
+	DecodeByte(b byte) rune
+}
+
+// Encodings of legacy pattern files.
+var (
+	T1     Encoding = t1Encoding{}      // Cork encoding of the EC fonts, used by dehyphn.tex
+	Latin1 Encoding = charmap.ISO8859_1 // ISO 8859-1
+	Latin2 Encoding = charmap.ISO8859_2 // ISO 8859-2, e.g. for Czech, Polish, Hungarian
+	KOI8R  Encoding = charmap.KOI8R     // KOI8-R, for Russian
+)
+
+// EncodingByName returns the encoding for a name as used in the texlive
+// section of hyph-utf8 headers ("ec", "t1", "ascii", ...) or registered
+// with IANA ("latin1", "ISO-8859-2", "KOI8-R", ...). UTF-8 and ASCII yield
+// a nil Encoding.
+func EncodingByName(name string) (Encoding, bool) {
+	switch strings.ToLower(name) {
+	case "", "utf-8", "utf8", "ascii", "us-ascii":
+		return nil, true
+	case "t1", "ec", "cork":
+		return T1, true
+	}
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil {
+		return nil, false
+	}
+	cm, ok := enc.(*charmap.Charmap)
+	return cm, ok
+}
+
+// t1Encoding is the Cork (T1) encoding. Its upper half holds the letters of
+// most European languages which use the Latin script. The lower half is taken
+// as ASCII, as the accents and ligatures there do not occur in pattern files.
+type t1Encoding struct{}
+
+var t1Upper = []rune("" +
+	"ĂĄĆČĎĚĘĞĹĽŁŃŇŊŐŔ" + // 0x80
+	"ŘŚŠŞŤŢŰŮŸŹŽŻĲİđ§" + // 0x90
+	"ăąćčďěęğĺľłńňŋőŕ" + // 0xA0
+	"řśšşťţűůÿźžżĳ¡¿£" + // 0xB0
+	"ÀÁÂÃÄÅÆÇÈÉÊËÌÍÎÏ" + // 0xC0
+	"ÐÑÒÓÔÕÖŒØÙÚÛÜÝÞẞ" + // 0xD0
+	"àáâãäåæçèéêëìíîï" + // 0xE0
+	"ðñòóôõöœøùúûüýþß") // 0xF0
+
+func (t1Encoding) DecodeByte(b byte) rune {
+	if b < 0x80 {
+		return rune(b)
+	}
+	return t1Upper[b-0x80]
+}
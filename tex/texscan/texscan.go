@@ -0,0 +1,316 @@
+// Package texscan tokenizes TeX hyphenation files. It is shared by the
+// pattern and exception readers in packages texpatterns and texexceptions.
+//
+// TeX files are not parsed line by line: entries are separated by arbitrary
+// white space, '%' starts a comment running to the end of the line, and
+// braces may appear anywhere. A file may contain any number of \patterns and
+// \hyphenation blocks in any order.
+package texscan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Kind is the kind of a token.
+type Kind uint8
+
+const (
+	Word       Kind = iota // a run of characters other than space, braces, '%' and '\'
+	Command                // a control sequence, e.g. \patterns
+	BeginGroup             // '{'
+	EndGroup               // '}'
+	Comment                // from '%' to the end of the line
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Word:
+		return "word"
+	case Command:
+		return "command"
+	case BeginGroup:
+		return "begin-group"
+	case EndGroup:
+		return "end-group"
+	case Comment:
+		return "comment"
+	}
+	return fmt.Sprintf("kind(%d)", uint8(k))
+}
+
+// Token is a lexical unit of a TeX file.
+type Token struct {
+	Kind Kind
+	Text string // the word, the name of a command without '\', or the comment without '%'
+	Line int    // 1-based line number of the start of the token
+}
+
+// Scanner splits TeX input into tokens.
+//
+// TeX's ^^ notation is decoded everywhere: ^^xx with two lowercase hex digits
+// stands for character code xx, ^^c for the character with code c±64 (e.g.
+// ^^M for a carriage return), and ^^^^xxxx for Unicode code point U+xxxx.
+// Character codes refer to the encoding of the input.
+type Scanner struct {
+	r      *bufio.Reader
+	enc    Encoding
+	line   int
+	sb     strings.Builder
+	unread rune // pushed back rune, or -1
+}
+
+// NewScanner creates a scanner reading from reader, which must deliver UTF-8.
+func NewScanner(reader io.Reader) *Scanner {
+	return NewEncodedScanner(reader, nil)
+}
+
+// NewEncodedScanner creates a scanner reading from reader, which delivers text
+// in encoding enc. Use a nil encoding for UTF-8.
+func NewEncodedScanner(reader io.Reader, enc Encoding) *Scanner {
+	return &Scanner{r: bufio.NewReader(reader), enc: enc, line: 1, unread: -1}
+}
+
+// Next returns the next token. It returns io.EOF at the end of the input.
+func (s *Scanner) Next() (Token, error) {
+	ch, err := s.skipSpace()
+	if err != nil {
+		return Token{}, err
+	}
+	tok := Token{Line: s.line}
+	switch ch {
+	case '{':
+		tok.Kind = BeginGroup
+	case '}':
+		tok.Kind = EndGroup
+	case '%':
+		tok.Kind = Comment
+		tok.Text, err = s.readComment()
+	case '\\':
+		tok.Kind = Command
+		tok.Text, err = s.readCommand()
+	default:
+		tok.Kind = Word
+		tok.Text, err = s.readWord(ch)
+	}
+	if err != nil && err != io.EOF {
+		return Token{}, err
+	}
+	return tok, nil
+}
+
+// CharCode reads a character code, as in \lccode assignments: a character
+// constant (`c or `\c), or a hexadecimal ("E4), octal ('344) or decimal (228)
+// number. Numbers below 256 refer to the input encoding. Leading white space
+// and an optional '=' are skipped.
+func (s *Scanner) CharCode() (rune, error) {
+	ch, err := s.skipSpace()
+	if err == nil && ch == '=' {
+		ch, err = s.skipSpace()
+	}
+	if err != nil {
+		return 0, err
+	}
+	base := 10
+	switch {
+	case ch == '`':
+		if ch, err = s.readRune(); err == nil && ch == '\\' {
+			ch, err = s.readRune()
+		}
+		return ch, err
+	case ch == '"':
+		base = 16
+	case ch == '\'':
+		base = 8
+	default:
+		s.unreadRune(ch)
+	}
+	n, digits := 0, 0
+	for {
+		if ch, err = s.readRune(); err != nil {
+			break
+		}
+		d := digitValue(ch)
+		if d < 0 || d >= base {
+			s.unreadRune(ch)
+			break
+		}
+		n, digits = n*base+d, digits+1
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("line %d: missing number", s.line)
+	}
+	if n < 256 {
+		return s.decodeByte(byte(n)), nil
+	}
+	return rune(n), nil
+}
+
+func digitValue(ch rune) int {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return int(ch - '0')
+	case ch >= 'A' && ch <= 'F':
+		return int(ch-'A') + 10
+	case ch >= 'a' && ch <= 'f':
+		return int(ch-'a') + 10
+	}
+	return -1
+}
+
+// readRune reads the next character, decoding the input encoding and the ^^
+// notation.
+func (s *Scanner) readRune() (rune, error) {
+	if ch := s.unread; ch >= 0 {
+		s.unread = -1
+		return ch, nil
+	}
+	ch, err := s.readRaw()
+	if err != nil || ch != '^' {
+		return ch, err
+	}
+	p, _ := s.r.Peek(7)
+	if len(p) < 2 || p[0] != '^' {
+		return ch, nil
+	}
+	if len(p) == 7 && p[1] == '^' && p[2] == '^' && isHex(p[3:7]) {
+		ch = rune(hexValue(p[3:7]))
+		_, err = s.r.Discard(7)
+		return ch, err
+	}
+	if len(p) >= 3 && isHex(p[1:3]) {
+		ch = s.decodeByte(byte(hexValue(p[1:3])))
+		_, err = s.r.Discard(3)
+		return ch, err
+	}
+	if p[1] < 0x80 {
+		ch = s.decodeByte(p[1] ^ 0x40)
+		_, err = s.r.Discard(2)
+		return ch, err
+	}
+	return ch, nil
+}
+
+func (s *Scanner) unreadRune(ch rune) {
+	s.unread = ch
+}
+
+// readRaw reads the next character of the input encoding.
+func (s *Scanner) readRaw() (rune, error) {
+	if s.enc == nil {
+		ch, _, err := s.r.ReadRune()
+		return ch, err
+	}
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	return s.decodeByte(b), nil
+}
+
+func (s *Scanner) decodeByte(b byte) rune {
+	if s.enc == nil || b < 0x80 {
+		return rune(b)
+	}
+	return s.enc.DecodeByte(b)
+}
+
+// isHex reports whether p consists of lowercase hex digits, as required by
+// the ^^ notation.
+func isHex(p []byte) bool {
+	for _, b := range p {
+		if !(b >= '0' && b <= '9' || b >= 'a' && b <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func hexValue(p []byte) int {
+	n := 0
+	for _, b := range p {
+		n = n*16 + digitValue(rune(b))
+	}
+	return n
+}
+
+func (s *Scanner) skipSpace() (rune, error) {
+	for {
+		ch, err := s.readRune()
+		if err != nil {
+			return 0, err
+		}
+		if ch == '\n' {
+			s.line++
+		} else if !unicode.IsSpace(ch) {
+			return ch, nil
+		}
+	}
+}
+
+func (s *Scanner) readComment() (string, error) {
+	s.sb.Reset()
+	for {
+		ch, err := s.readRune()
+		if err != nil {
+			return s.sb.String(), err
+		}
+		if ch == '\n' {
+			s.line++
+			return strings.TrimSuffix(s.sb.String(), "\r"), nil
+		}
+		s.sb.WriteRune(ch)
+	}
+}
+
+// readCommand reads a control word (a backslash followed by letters) or a
+// control symbol (a backslash followed by any other character).
+func (s *Scanner) readCommand() (string, error) {
+	s.sb.Reset()
+	ch, err := s.readRune()
+	if err != nil {
+		return "", err
+	}
+	s.sb.WriteRune(ch)
+	if !isLetter(ch) {
+		if ch == '\n' {
+			s.line++
+		}
+		return s.sb.String(), nil
+	}
+	for {
+		if ch, err = s.readRune(); err != nil {
+			return s.sb.String(), err
+		}
+		if !isLetter(ch) {
+			s.unreadRune(ch)
+			return s.sb.String(), nil
+		}
+		s.sb.WriteRune(ch)
+	}
+}
+
+func (s *Scanner) readWord(first rune) (string, error) {
+	s.sb.Reset()
+	s.sb.WriteRune(first)
+	for {
+		ch, err := s.readRune()
+		if err != nil {
+			return s.sb.String(), err
+		}
+		if unicode.IsSpace(ch) || ch == '{' || ch == '}' || ch == '%' || ch == '\\' {
+			s.unreadRune(ch)
+			return s.sb.String(), nil
+		}
+		s.sb.WriteRune(ch)
+	}
+}
+
+// isLetter reports whether ch may be part of a control word. As with TeX's
+// default category codes, these are the ASCII letters only.
+func isLetter(ch rune) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
@@ -0,0 +1,144 @@
+package texscan
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	s := NewScanner(strings.NewReader(`\patterns{% comment
+.ab1c a2b % another
+}\message{x\%y}`))
+	var got []Token
+	for {
+		tok, err := s.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, tok)
+	}
+	want := []Token{
+		{Command, "patterns", 1},
+		{BeginGroup, "", 1},
+		{Comment, " comment", 1},
+		{Word, ".ab1c", 2},
+		{Word, "a2b", 2},
+		{Comment, " another", 2},
+		{EndGroup, "", 3},
+		{Command, "message", 3},
+		{BeginGroup, "", 3},
+		{Word, "x", 3},
+		{Command, "%", 3},
+		{Word, "y", 3},
+		{EndGroup, "", 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tokens mismatch:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestBlockReader(t *testing.T) {
+	b := NewBlockReader(strings.NewReader(`% header
+\hyphenation{ta-ble}
+\patterns{a1b b1c % comment
+c1d}
+\message{Test patterns}
+\patterns{
+  d1e
+}
+\endinput
+\patterns{e1f}`), "patterns")
+	var comments []string
+	var message string
+	b.Comment = func(tok Token) { comments = append(comments, tok.Text) }
+	b.Message = func(text string) { message = text }
+	var words []string
+	for {
+		tok, err := b.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		words = append(words, tok.Text)
+	}
+	if !reflect.DeepEqual(words, []string{"a1b", "b1c", "c1d", "d1e"}) {
+		t.Errorf("unexpected words %v", words)
+	}
+	if !reflect.DeepEqual(comments, []string{" header", " comment"}) {
+		t.Errorf("unexpected comments %q", comments)
+	}
+	if message != "Test patterns" {
+		t.Errorf("unexpected message %q", message)
+	}
+}
+
+func TestBlockReaderErrors(t *testing.T) {
+	for _, src := range []string{`\patterns{a1b`, `\patterns a1b`} {
+		b := NewBlockReader(strings.NewReader(src), "patterns")
+		var err error
+		for err == nil {
+			_, err = b.Next()
+		}
+		if err == io.EOF {
+			t.Errorf("expected an error for %q", src)
+		}
+	}
+}
+
+func TestScannerEncodings(t *testing.T) {
+	tests := []struct {
+		enc  Encoding
+		src  string
+		want string
+	}{
+		{nil, "gr^^c3^^9f^^e4", "gr\u00c3\u009f\u00e4"}, // codes are code points for UTF-8 input
+		{nil, "^^^^00df^^?", "ß\x7f"},
+		{T1, "gr\xf6\xffe ^^e4", "größe"}, // space ends the word
+		{Latin2, "\xb3\xf3d\xbc", "łódź"},
+		{KOI8R, "\xd3\xcc\xcf\xd7\xcf", "слово"},
+	}
+	for _, tt := range tests {
+		s := NewEncodedScanner(strings.NewReader(tt.src), tt.enc)
+		tok, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Text != tt.want {
+			t.Errorf("decoding %q: got %q, want %q", tt.src, tok.Text, tt.want)
+		}
+	}
+}
+
+func TestBlockReaderLccode(t *testing.T) {
+	b := NewEncodedBlockReader(strings.NewReader("\\lccode`\\^^c4=`\\^^e4 \\lccode\"DF=\"DF\n"+
+		"\\lccode 39 = 39 \\lccode`\\-=0 \\patterns{^^e41a}"), "patterns", T1)
+	letters := make(map[rune]rune)
+	b.Letter = func(letter, lower rune) { letters[letter] = lower }
+	tok, err := b.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Text != "ä1a" {
+		t.Errorf("unexpected pattern %q", tok.Text)
+	}
+	want := map[rune]rune{'Ä': 'ä', 'ẞ': 'ẞ', '\'': '\'', '-': 0}
+	if !reflect.DeepEqual(letters, want) {
+		t.Errorf("unexpected letters %q", letters)
+	}
+}
+
+func TestEncodingByName(t *testing.T) {
+	for name, want := range map[string]Encoding{"ec": T1, "T1": T1, "utf-8": nil, "latin2": Latin2, "KOI8-R": KOI8R} {
+		if enc, ok := EncodingByName(name); !ok || enc != want {
+			t.Errorf("unexpected encoding %v for %s", enc, name)
+		}
+	}
+	if _, ok := EncodingByName("no-such-encoding"); ok {
+		t.Errorf("expected unknown encoding")
+	}
+}
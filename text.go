@@ -0,0 +1,186 @@
+package hyphenate
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer splits prose into words to hyphenate and other text.
+//
+// Token inspects the start of text and returns the length in bytes of the
+// first token, together with a flag telling if the token is a word to be
+// hyphenated. Tokens which are not words are copied to the output unchanged.
+// For non-empty text, n has to be > 0.
+//
+// atEOF is false if text may continue beyond its end. A tokenizer should then
+// return n == 0 to request more text if a token touches the end of text.
+type Tokenizer interface {
+	Token(text string, atEOF bool) (n int, word bool)
+}
+
+// TokenizerFunc adapts a function to the Tokenizer interface.
+type TokenizerFunc func(text string, atEOF bool) (n int, word bool)
+
+// Token implements Tokenizer.
+func (f TokenizerFunc) Token(text string, atEOF bool) (int, bool) {
+	return f(text, atEOF)
+}
+
+// WordTokenizer is the default tokenizer. Words are maximal runs of letters
+// (including combining marks). Runs of letters mixed with digits, such as
+// "mp3" or "B2B", are not hyphenated. Everything else, e.g. whitespace,
+// punctuation, quotes and numbers, is copied verbatim.
+var WordTokenizer Tokenizer = TokenizerFunc(func(text string, atEOF bool) (int, bool) {
+	return tokenizeWords(text, atEOF, isWordRune)
+})
+
+// tokenizeWords implements WordTokenizer, with isWord telling which runes may
+// be part of a word.
+func tokenizeWords(text string, atEOF bool, isWord func(rune) bool) (int, bool) {
+	if text == "" {
+		return 0, false
+	}
+	if !atEOF && !utf8.FullRuneInString(text) {
+		return 0, false
+	}
+	r, size := utf8.DecodeRuneInString(text)
+	if !isWord(r) {
+		return size, false
+	}
+	word := true
+	n := 0
+	for n < len(text) {
+		if !atEOF && !utf8.FullRuneInString(text[n:]) {
+			return 0, false
+		}
+		r, size = utf8.DecodeRuneInString(text[n:])
+		if !isWord(r) {
+			return n, word
+		}
+		if unicode.IsDigit(r) {
+			word = false
+		}
+		n += size
+	}
+	if !atEOF {
+		return 0, false // word may continue
+	}
+	return n, word
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r))
+}
+
+// TextHyphenator hyphenates running text. It tokenizes text, hyphenates
+// words with a dictionary and leaves everything else byte-for-byte intact.
+//
+// Dictionary and Encoder are mandatory. If Tokenizer is nil, WordTokenizer
+// is used, extended by the letters declared by the dictionary's pattern
+// source (see LetterReader). If Options is nil, the dictionary's default
+// options are used.
+type TextHyphenator struct {
+	Dictionary *Dictionary
+	Encoder    Encoder
+	Tokenizer  Tokenizer
+	Options    *Options
+}
+
+// String returns text with all words hyphenated.
+//
+// Example, using the Hyphen encoder with US-English patterns:
+//
+//	`"Algorithms," she said.` => `"Al-go-rithms," she said.`
+func (th *TextHyphenator) String(text string) string {
+	buf, _ := th.appendText(make([]byte, 0, len(text)+len(text)/8), text, true)
+	return string(buf)
+}
+
+// Copy reads text from r until EOF and writes it with all words hyphenated
+// to w.
+func (th *TextHyphenator) Copy(w io.Writer, r io.Reader) error {
+	const chunkSize = 4096
+	br := bufio.NewReaderSize(r, chunkSize)
+	pending := make([]byte, 0, chunkSize)
+	out := make([]byte, 0, 2*chunkSize)
+	chunk := make([]byte, chunkSize)
+	for {
+		n, err := br.Read(chunk)
+		pending = append(pending, chunk[:n]...)
+		atEOF := errors.Is(err, io.EOF)
+		if err != nil && !atEOF {
+			return err
+		}
+		text := string(pending)
+		var consumed int
+		out, consumed = th.appendText(out[:0], text, atEOF)
+		if _, werr := w.Write(out); werr != nil {
+			return werr
+		}
+		pending = append(pending[:0], text[consumed:]...)
+		if atEOF {
+			return nil
+		}
+	}
+}
+
+// appendText appends the hyphenated tokens of text to dst and returns the
+// number of bytes consumed from text. If atEOF is false, a token touching the
+// end of text is left unprocessed.
+func (th *TextHyphenator) appendText(dst []byte, text string, atEOF bool) ([]byte, int) {
+	tokenizer := th.Tokenizer
+	if tokenizer == nil {
+		tokenizer = th.Dictionary.wordTokenizer()
+	}
+	opts := th.Dictionary.Options
+	if th.Options != nil {
+		opts = *th.Options
+	}
+	i := 0
+	for i < len(text) {
+		n, word := tokenizer.Token(text[i:], atEOF)
+		if n <= 0 {
+			break
+		}
+		token := text[i : i+n]
+		if word {
+			dst = th.Encoder.Encode(dst, token, th.Dictionary.BreaksWith(token, opts))
+		} else {
+			dst = append(dst, token...)
+		}
+		i += n
+	}
+	if atEOF && i < len(text) { // tokenizer gave up, keep the rest verbatim
+		dst = append(dst, text[i:]...)
+		i = len(text)
+	}
+	return dst, i
+}
+
+// HyphenateText returns text with all words hyphenated and marked by encoder
+// enc, using the default tokenizer and the dictionary's default options.
+// Whitespace, punctuation, numbers etc. are kept as they are.
+//
+// Use a TextHyphenator for more control.
+func (dict *Dictionary) HyphenateText(text string, enc Encoder) string {
+	th := TextHyphenator{Dictionary: dict, Encoder: enc}
+	return th.String(text)
+}
+
+// wordTokenizer returns WordTokenizer, extended by the declared letters of
+// the dictionary.
+func (dict *Dictionary) wordTokenizer() Tokenizer {
+	if len(dict.letters) == 0 {
+		return WordTokenizer
+	}
+	isWord := func(r rune) bool {
+		_, ok := dict.letters[r]
+		return ok || isWordRune(r)
+	}
+	return TokenizerFunc(func(text string, atEOF bool) (int, bool) {
+		return tokenizeWords(text, atEOF, isWord)
+	})
+}
@@ -0,0 +1,62 @@
+package hyphenate
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newTextTestDictionary(t *testing.T) *Dictionary {
+	t.Helper()
+	dict, err := LoadPatterns("text", &slicePatternReader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict.AddException("table", []int{0, 0, 1, 0, 0})
+	dict.AddException("führung", []int{0, 0, 0, 1, 0, 0, 0})
+	return dict
+}
+
+func TestHyphenateText(t *testing.T) {
+	dict := newTextTestDictionary(t)
+	text := "»Führung«, said the table-maker (no. 42, mp3table)…\n\tTABLE!"
+	want := "»Füh-rung«, said the ta-ble-maker (no. 42, mp3table)…\n\tTA-BLE!"
+	if got := dict.HyphenateText(text, Hyphen); got != want {
+		t.Fatalf("text mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestTextHyphenatorCopy(t *testing.T) {
+	dict := newTextTestDictionary(t)
+	text := strings.Repeat("Führung und table; ", 500)
+	want := strings.Repeat("Füh|rung und ta|ble; ", 500)
+	th := TextHyphenator{Dictionary: dict, Encoder: Separator("|")}
+	var out strings.Builder
+	// reading one byte at a time splits words and UTF-8 sequences
+	if err := th.Copy(&out, iotest.OneByteReader(strings.NewReader(text))); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Fatalf("copy mismatch, got %q...", out.String()[:40])
+	}
+}
+
+func TestTextHyphenatorTokenizer(t *testing.T) {
+	dict := newTextTestDictionary(t)
+	// a tokenizer which treats every run of non-space characters as a word
+	fields := TokenizerFunc(func(text string, atEOF bool) (int, bool) {
+		if text[0] == ' ' {
+			return 1, false
+		}
+		if i := strings.IndexByte(text, ' '); i >= 0 {
+			return i, true
+		} else if atEOF {
+			return len(text), true
+		}
+		return 0, false
+	})
+	th := TextHyphenator{Dictionary: dict, Encoder: Hyphen, Tokenizer: fields}
+	if got := th.String("table tables"); got != "ta-ble tables" {
+		t.Fatalf("custom tokenizer: got %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package txt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// PatternReader streams the patterns of a .pat.txt file.
+type PatternReader struct {
+	Alphabet map[rune]rune       // letters from .chr.txt, see ReadAlphabet
+	License  *hyphenate.Metadata // metadata from .lic.txt, see texpatterns.ParseMetadata
+
+	scanner     *bufio.Scanner
+	line        int
+	pending     []string // further patterns of the current line
+	replacement *hyphenate.Replacement
+}
+
+// NewPatternReader creates a reader for a .pat.txt file, which holds one
+// pattern per line.
+//
+// The reader implements hyphenate.LetterReader for Alphabet, and
+// hyphenate.MetadataReader and hyphenate.HyphenminsReader for License. Set
+// these fields before loading the patterns. It implements hyphenate.LineReader
+// as well.
+func NewPatternReader(reader io.Reader) *PatternReader {
+	return &PatternReader{scanner: bufio.NewScanner(reader)}
+}
+
+// Next returns the next pattern as (sequence, weights).
+// It returns io.EOF when exhausted.
+func (r *PatternReader) Next() ([]rune, []int, error) {
+	for len(r.pending) == 0 {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, io.EOF
+		}
+		r.line++
+		r.pending = strings.Fields(r.scanner.Text())
+	}
+	s := r.pending[0]
+	r.pending = r.pending[1:]
+	p, err := hyphenate.ParsePattern(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("line %d: %w", r.line, err)
+	}
+	r.replacement = p.Replacement
+	return p.Sequence, p.Weights, nil
+}
+
+// Replacement returns the replacement of the pattern most recently returned
+// by Next if it is a non-standard pattern, and nil otherwise.
+func (r *PatternReader) Replacement() *hyphenate.Replacement {
+	return r.replacement
+}
+
+// Line returns the line of the pattern most recently returned by Next.
+func (r *PatternReader) Line() int {
+	return r.line
+}
+
+// Letters returns Alphabet.
+func (r *PatternReader) Letters() map[rune]rune {
+	return r.Alphabet
+}
+
+// Metadata returns License.
+func (r *PatternReader) Metadata() *hyphenate.Metadata {
+	return r.License
+}
+
+// Hyphenmins returns the typesetting hyphenmins of License, or 0 if they are
+// unknown.
+func (r *PatternReader) Hyphenmins() (left, right int) {
+	if r.License == nil {
+		return 0, 0
+	}
+	return r.License.TypesettingMins.Left, r.License.TypesettingMins.Right
+}
+
+// ExceptionReader streams the exceptions of a .hyp.txt file.
+type ExceptionReader struct {
+	scanner *bufio.Scanner
+	pending []string // further exceptions of the current line
+}
+
+// NewExceptionReader creates a reader for a .hyp.txt file, which holds one
+// exception per line, with breaks marked by hyphens (e.g. "ta-ble").
+func NewExceptionReader(reader io.Reader) *ExceptionReader {
+	return &ExceptionReader{scanner: bufio.NewScanner(reader)}
+}
+
+// Next returns the next exception as (word, positions).
+// It returns io.EOF when exhausted.
+func (r *ExceptionReader) Next() (string, []int, error) {
+	for len(r.pending) == 0 {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return "", nil, err
+			}
+			return "", nil, io.EOF
+		}
+		r.pending = strings.Fields(r.scanner.Text())
+	}
+	s := r.pending[0]
+	r.pending = r.pending[1:]
+	word, positions := hyphenate.ParseException(s)
+	return word, positions, nil
+}
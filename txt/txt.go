@@ -0,0 +1,111 @@
+// Package txt reads the plain-text files of hyph-utf8, which publishes every
+// language as a set of files
+//
+//	hyph-de-1996.pat.txt   patterns, one per line
+//	hyph-de-1996.hyp.txt   exceptions, one per line
+//	hyph-de-1996.chr.txt   characters of the language with their case variants
+//	hyph-de-1996.lic.txt   licence and metadata
+//
+// Only the patterns are mandatory.
+package txt
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/npillmayer/hyphenate"
+	"github.com/npillmayer/hyphenate/tex/texpatterns"
+)
+
+// Files are the plain-text files of a language. Patterns is mandatory, the
+// other files may be nil.
+type Files struct {
+	Patterns   io.Reader // .pat.txt
+	Exceptions io.Reader // .hyp.txt
+	Characters io.Reader // .chr.txt
+	License    io.Reader // .lic.txt
+}
+
+// Load loads a language from its plain-text files. The characters of
+// Files.Characters become the letters of the dictionary, with their case
+// mappings (see hyphenate.LetterReader). Files.License is attached to
+// Dictionary.Metadata and provides the hyphenmins.
+func Load(name string, files Files) (*hyphenate.Dictionary, error) {
+	if files.Patterns == nil {
+		return nil, errors.New("missing patterns")
+	}
+	r := NewPatternReader(files.Patterns)
+	var err error
+	if files.Characters != nil {
+		if r.Alphabet, err = ReadAlphabet(files.Characters); err != nil {
+			return nil, err
+		}
+	}
+	if files.License != nil {
+		if r.License, err = texpatterns.ParseMetadata(files.License); err != nil {
+			return nil, err
+		}
+	}
+	dict, err := hyphenate.LoadPatterns(name, r)
+	if err != nil {
+		return nil, err
+	}
+	if files.Exceptions != nil {
+		err = dict.LoadExceptions(NewExceptionReader(files.Exceptions))
+	}
+	return dict, err
+}
+
+// LoadFS loads the language with BCP 47 tag tag from the plain-text files in
+// fsys, i.e. from hyph-<tag>.pat.txt and, if present, hyph-<tag>.hyp.txt,
+// hyph-<tag>.chr.txt and hyph-<tag>.lic.txt. Use os.DirFS for a directory.
+//
+// Example:
+//
+//	dict, err := txt.LoadFS(os.DirFS("hyph-utf8/patterns/txt"), "de-1996")
+func LoadFS(fsys fs.FS, tag string) (*hyphenate.Dictionary, error) {
+	var files Files
+	for _, f := range []struct {
+		ext      string
+		reader   *io.Reader
+		optional bool
+	}{
+		{".pat.txt", &files.Patterns, false},
+		{".hyp.txt", &files.Exceptions, true},
+		{".chr.txt", &files.Characters, true},
+		{".lic.txt", &files.License, true},
+	} {
+		file, err := fsys.Open("hyph-" + tag + f.ext)
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		*f.reader = file
+	}
+	return Load(tag, files)
+}
+
+// ReadAlphabet reads a .chr.txt file. Every line holds a lowercase character
+// followed by its uppercase variants, e.g. "aA" or "ſ" (without variants).
+// The result maps every character to its lowercase form.
+func ReadAlphabet(reader io.Reader) (map[rune]rune, error) {
+	alphabet := make(map[rune]rune)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lower, _ := utf8.DecodeRuneInString(line)
+		for _, r := range line {
+			alphabet[r] = lower
+		}
+	}
+	return alphabet, scanner.Err()
+}
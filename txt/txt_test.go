@@ -0,0 +1,107 @@
+package txt
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var fixture = fstest.MapFS{
+	"hyph-xx.pat.txt": {Data: []byte("1ba\na1b\n.zu1\n")},
+	"hyph-xx.hyp.txt": {Data: []byte("ta-ble\nre-cord\n")},
+	"hyph-xx.chr.txt": {Data: []byte("aA\nbB\nlL\ntT\nzZ\nuU\n'\n")},
+	"hyph-xx.lic.txt": {Data: []byte(`title: Test Patterns
+version: 1.0
+hyphenmins:
+    typesetting:
+        left: 1
+        right: 3
+==========
+Some more text.
+`)},
+}
+
+func TestLoadFS(t *testing.T) {
+	dict, err := LoadFS(fixture, "xx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Metadata == nil || dict.Metadata.Title != "Test Patterns" || dict.Metadata.Version != "1.0" {
+		t.Fatalf("metadata should be taken from .lic.txt, is %+v", dict.Metadata)
+	}
+	if dict.Options.LeftMin != 1 || dict.Options.RightMin != 3 {
+		t.Fatalf("hyphenmins should be 1/3, are %d/%d", dict.Options.LeftMin, dict.Options.RightMin)
+	}
+	if !dict.IsLetter('\'') {
+		t.Errorf("' should be a letter of the alphabet")
+	}
+	tests := map[string]string{
+		"TABLE":   "TA-BLE",
+		"record":  "re-cord",
+		"zubabab": "zu-ba-bab",
+	}
+	for word, want := range tests {
+		if h := dict.HyphenationString(word); h != want {
+			t.Errorf("%s should be %s, is %s", word, want, h)
+		}
+	}
+}
+
+func TestLoadFSOptionalFiles(t *testing.T) {
+	fsys := fstest.MapFS{"hyph-xx.pat.txt": fixture["hyph-xx.pat.txt"]}
+	dict, err := LoadFS(fsys, "xx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dict.Metadata != nil || dict.Options.LeftMin != 2 {
+		t.Fatalf("dictionary without .lic.txt should have default settings")
+	}
+	if _, err := LoadFS(fsys, "yy"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("missing patterns should be reported as fs.ErrNotExist, error is %v", err)
+	}
+}
+
+func TestReadAlphabet(t *testing.T) {
+	alphabet, err := ReadAlphabet(strings.NewReader("aA\n\näÄ\nſ\nıI\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[rune]rune{'a': 'a', 'A': 'a', 'ä': 'ä', 'Ä': 'ä', 'ſ': 'ſ', 'ı': 'ı', 'I': 'ı'}
+	if !reflect.DeepEqual(alphabet, want) {
+		t.Fatalf("alphabet should be %v, is %v", want, alphabet)
+	}
+}
+
+func TestPatternReaderError(t *testing.T) {
+	r := NewPatternReader(strings.NewReader("a1b\na12b\n"))
+	if _, _, err := r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if _, _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("bad pattern should be reported for line 2, error is %v", err)
+	}
+}
+
+func TestExceptionReader(t *testing.T) {
+	r := NewExceptionReader(strings.NewReader("ta-ble\n\nas-so-ciate\n"))
+	var got []string
+	for {
+		word, positions, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		got = append(got, word)
+		if word == "associate" && !reflect.DeepEqual(positions, []int{0, 0, 1, 0, 1, 0, 0, 0, 0}) {
+			t.Errorf("positions of associate are %v", positions)
+		}
+	}
+	if want := []string{"table", "associate"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("exceptions should be %v, are %v", want, got)
+	}
+}
@@ -0,0 +1,336 @@
+// Package wortliste reads the German "Wortliste" of the Trennmuster project
+// (https://repo.or.cz/wortliste.git), from which the German hyph-utf8
+// patterns are generated.
+package wortliste
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+// Category classifies the breaks of the Wortliste. Categories are bits, so
+// sets of categories may be formed with '|'.
+type Category uint8
+
+// Categories of breaks, with their markers in the Wortliste.
+const (
+	Syllable Category = 1 << iota // '-' ordinary break between syllables
+	Compound                      // '=' boundary between the parts of a compound
+	Prefix                        // '<' break after a prefix
+	Suffix                        // '>' break before a suffix
+	Unwanted                      // '.' possible, but unwanted break; a modifier of the above
+
+	// AllBreaks selects all breaks except for unwanted ones.
+	AllBreaks = Syllable | Compound | Prefix | Suffix
+)
+
+// Spelling selects a spelling variant of the Wortliste.
+type Spelling uint8
+
+// Spelling variants. Words which lack a variant of their own fall back to
+// the more general variants.
+const (
+	Reformed         Spelling = iota // reformed orthography (de-1996)
+	Traditional                      // traditional orthography (de-1901)
+	SwissReformed                    // Swiss, or in capitals, reformed (de-CH-1996)
+	SwissTraditional                 // Swiss, or in capitals, traditional (de-CH-1901)
+)
+
+// fields lists the fields of a line holding the hyphenated word for a
+// spelling, in order of precedence.
+var fields = [...][]int{
+	Reformed:         {4, 2},
+	Traditional:      {3, 2},
+	SwissReformed:    {7, 5, 4, 2},
+	SwissTraditional: {8, 6, 5, 3, 2},
+}
+
+// Break is a break of an Entry.
+type Break struct {
+	Pos         int                    // rune offset in Entry.Word; the break is located before this rune
+	Category    Category               // kind of break, possibly with modifier Unwanted
+	Replacement *hyphenate.Replacement // for non-standard breaks; Start is relative to Entry.Word
+}
+
+// In reports whether the break belongs to the set of categories. Unwanted
+// breaks belong to sets which include Unwanted only.
+func (brk Break) In(set Category) bool {
+	if brk.Category&Unwanted != 0 && set&Unwanted == 0 {
+		return false
+	}
+	return brk.Category&set&^Unwanted != 0
+}
+
+// Entry is a word of the Wortliste in one spelling.
+type Entry struct {
+	Word   string  // the word without breaks, e.g. "Abbildung"
+	Breaks []Break // breaks ordered by position
+	Line   int     // line of the entry in the Wortliste
+}
+
+// Positions returns the breaks of the entry which belong to set, in the form
+// of hyphenate.ExceptionReader: positions[i] is 1 for a break before rune i,
+// and 0 otherwise.
+func (e Entry) Positions(set Category) []int {
+	positions := make([]int, utf8.RuneCountInString(e.Word))
+	for _, brk := range e.Breaks {
+		if brk.In(set) && brk.Pos < len(positions) {
+			positions[brk.Pos] = 1
+		}
+	}
+	return positions
+}
+
+// Replacements returns the replacements of the non-standard breaks of the
+// entry which belong to set, by position, or nil if there are none.
+func (e Entry) Replacements(set Category) map[int]*hyphenate.Replacement {
+	var reps map[int]*hyphenate.Replacement
+	for _, brk := range e.Breaks {
+		if brk.Replacement != nil && brk.In(set) {
+			if reps == nil {
+				reps = make(map[int]*hyphenate.Replacement)
+			}
+			reps[brk.Pos] = brk.Replacement
+		}
+	}
+	return reps
+}
+
+// HyphenationString returns the word with hyphens inserted at the breaks
+// which belong to set, e.g. "Ab-bil-dung". Non-standard breaks are applied
+// ("Zuk-ker").
+func (e Entry) HyphenationString(set Category) string {
+	word := []rune(e.Word)
+	var sb strings.Builder
+	prev := 0
+	for _, brk := range e.Breaks {
+		if !brk.In(set) {
+			continue
+		}
+		from, to, pre, post := brk.Pos, brk.Pos, "", ""
+		if rep := brk.Replacement; rep != nil {
+			from, to, pre, post = rep.Start, rep.Start+rep.Cut, rep.Pre, rep.Post
+		}
+		if from < prev { // overlapping replacements
+			continue
+		}
+		sb.WriteString(string(word[prev:from]))
+		sb.WriteString(pre + "-" + post)
+		prev = to
+	}
+	sb.WriteString(string(word[prev:]))
+	return sb.String()
+}
+
+// Reader streams the entries of the Wortliste. Lines have the form
+//
+//	Abbildung;Ab=bil-dung
+//	Zucker;-2-;Zu{ck/k-k}er;Zu-cker
+//
+// The first field holds the word, the following fields hold the hyphenated
+// word for all spellings (field 2) or for single spellings (fields 3–8),
+// where empty fields are written as "-3-" and so on. Hyphenated words use the
+// markers
+//
+//	"-"          ordinary break
+//	"=", "=="    boundary between the parts of a compound
+//	"<"          break after a prefix
+//	">"          break before a suffix
+//	"."          unwanted break, usually in combination with the above
+//	"{ck/k-k}"   non-standard break, with the unbroken and the broken spelling
+//
+// Lines starting with '#' are comments; comments may also follow the fields.
+type Reader struct {
+	scanner  *bufio.Scanner
+	spelling Spelling
+	line     int
+}
+
+// NewReader creates a reader for the entries of the Wortliste in a spelling.
+// Words which do not exist in the spelling are skipped.
+func NewReader(reader io.Reader, spelling Spelling) *Reader {
+	if int(spelling) >= len(fields) {
+		spelling = Reformed
+	}
+	return &Reader{scanner: bufio.NewScanner(reader), spelling: spelling}
+}
+
+// Next returns the next entry. It returns io.EOF when exhausted.
+func (r *Reader) Next() (Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line, _, _ := strings.Cut(r.scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		field := r.field(strings.Split(line, ";"))
+		if field == "" {
+			continue // not in this spelling
+		}
+		e, err := parseEntry(field)
+		if err != nil {
+			return Entry{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		e.Line = r.line
+		return e, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// field selects the hyphenated word of a line for the reader's spelling, or
+// returns "" if the word does not exist in the spelling.
+func (r *Reader) field(values []string) string {
+	for _, n := range fields[r.spelling] {
+		if n > len(values) {
+			continue
+		}
+		value := strings.TrimSpace(values[n-1])
+		if value != "" && value != "-"+strconv.Itoa(n)+"-" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseEntry parses a hyphenated word.
+func parseEntry(field string) (Entry, error) {
+	var word, markers []rune
+	var breaks []Break
+	// flush adds a break for the markers in front of the current position
+	flush := func() {
+		if len(markers) > 0 && len(word) > 0 {
+			breaks = append(breaks, Break{Pos: len(word), Category: category(markers)})
+		}
+		markers = markers[:0]
+	}
+	text := []rune(field)
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '-', '=', '<', '>', '.', '·':
+			markers = append(markers, c)
+		case '{', '[':
+			end := i + 1
+			for end < len(text) && text[end] != '}' && text[end] != ']' {
+				end++
+			}
+			if end == len(text) {
+				return Entry{}, fmt.Errorf("unclosed %c in %q", c, field)
+			}
+			flush()
+			brk, unbroken, err := parseAlternative(string(text[i+1:end]), len(word))
+			if err != nil {
+				return Entry{}, fmt.Errorf("%w in %q", err, field)
+			}
+			if brk != nil {
+				breaks = append(breaks, *brk)
+			}
+			word = append(word, unbroken...)
+			i = end
+		default:
+			flush()
+			word = append(word, c)
+		}
+	}
+	return Entry{Word: string(word), Breaks: breaks}, nil
+}
+
+// parseAlternative parses a non-standard break "ck/k-k" at rune offset start
+// of a word. It returns the break, if any, and the unbroken spelling.
+func parseAlternative(alt string, start int) (*Break, []rune, error) {
+	unbroken, broken, ok := strings.Cut(alt, "/")
+	if !ok {
+		return nil, nil, fmt.Errorf("alternative %q without '/'", alt)
+	}
+	e, err := parseEntry(broken)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := []rune(unbroken)
+	if len(e.Breaks) == 0 {
+		return nil, text, nil
+	}
+	word, b := []rune(e.Word), e.Breaks[0]
+	return &Break{
+		Pos:      start + min(b.Pos, len(text)),
+		Category: b.Category,
+		Replacement: &hyphenate.Replacement{
+			Pre:   string(word[:b.Pos]),
+			Post:  string(word[b.Pos:]),
+			Start: start,
+			Cut:   len(text),
+		},
+	}, text, nil
+}
+
+// category returns the category of a sequence of markers.
+func category(markers []rune) Category {
+	var c Category
+	s := string(markers)
+	switch {
+	case strings.Contains(s, "="):
+		c = Compound
+	case strings.Contains(s, "<"):
+		c = Prefix
+	case strings.Contains(s, ">"):
+		c = Suffix
+	default:
+		c = Syllable
+	}
+	if strings.Contains(s, ".") {
+		c |= Unwanted
+	}
+	return c
+}
+
+// ExceptionReader streams the entries of the Wortliste as hyphenation
+// exceptions, with the breaks of a set of categories. It implements
+// hyphenate.ExceptionReplacementReader for non-standard breaks.
+type ExceptionReader struct {
+	entries *Reader
+	set     Category
+	reps    map[int]*hyphenate.Replacement
+}
+
+// LoadExceptions adds the words of the Wortliste in a spelling to dict, with
+// the breaks of the categories in set.
+//
+// Example:
+//
+//	err := wortliste.LoadExceptions(dict, f, wortliste.Reformed, wortliste.Compound)
+func LoadExceptions(dict *hyphenate.Dictionary, reader io.Reader, spelling Spelling, set Category) error {
+	return dict.LoadExceptions(NewExceptionReader(reader, spelling, set))
+}
+
+// NewExceptionReader creates a reader for the words of the Wortliste in a
+// spelling, with the breaks of the categories in set, e.g. Compound for
+// compound boundaries only or AllBreaks for all syllable breaks.
+func NewExceptionReader(reader io.Reader, spelling Spelling, set Category) *ExceptionReader {
+	return &ExceptionReader{entries: NewReader(reader, spelling), set: set}
+}
+
+// Next returns the next exception as (word, positions).
+// It returns io.EOF when exhausted.
+func (r *ExceptionReader) Next() (string, []int, error) {
+	e, err := r.entries.Next()
+	if err != nil {
+		return "", nil, err
+	}
+	r.reps = e.Replacements(r.set)
+	return e.Word, e.Positions(r.set), nil
+}
+
+// Replacements returns the replacements of the non-standard breaks of the
+// exception most recently returned by Next, or nil if there are none.
+func (r *ExceptionReader) Replacements() map[int]*hyphenate.Replacement {
+	return r.reps
+}
@@ -0,0 +1,119 @@
+package wortliste
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/hyphenate"
+)
+
+const fixture = `# Test entries
+Abbildung;Ab=bil-dung
+Zucker;-2-;Zu{ck/k-k}er;Zu-cker
+Schiffahrt;-2-;Schi{ff/ff=f}ahrt;-4-
+Schifffahrt;-2-;-3-;Schiff=fahrt
+Freiheit;Frei>heit   # suffix
+Verlag;Ver<lag
+Urinstinkt;Ur=in.stinkt
+`
+
+func readAll(t *testing.T, spelling Spelling) []Entry {
+	t.Helper()
+	r := NewReader(strings.NewReader(fixture), spelling)
+	var entries []Entry
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return entries
+		} else if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+func TestReader(t *testing.T) {
+	var got []string
+	for _, e := range readAll(t, Reformed) {
+		got = append(got, e.HyphenationString(AllBreaks))
+	}
+	want := []string{"Ab-bil-dung", "Zu-cker", "Schiff-fahrt", "Frei-heit", "Ver-lag", "Ur-instinkt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("reformed spelling should be %v, is %v", want, got)
+	}
+	got = got[:0]
+	for _, e := range readAll(t, Traditional) {
+		got = append(got, e.HyphenationString(AllBreaks|Unwanted))
+	}
+	want = []string{"Ab-bil-dung", "Zuk-ker", "Schiff-fahrt", "Frei-heit", "Ver-lag", "Ur-in-stinkt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("traditional spelling should be %v, is %v", want, got)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	entries := readAll(t, Reformed)
+	abbildung := entries[0]
+	if abbildung.Line != 2 {
+		t.Errorf("Abbildung should be in line 2, is in line %d", abbildung.Line)
+	}
+	want := []Break{{Pos: 2, Category: Compound}, {Pos: 5, Category: Syllable}}
+	if !reflect.DeepEqual(abbildung.Breaks, want) {
+		t.Fatalf("breaks of Abbildung should be %v, are %v", want, abbildung.Breaks)
+	}
+	if h := abbildung.HyphenationString(Compound); h != "Ab-bildung" {
+		t.Errorf("compound boundaries of Abbildung should be Ab-bildung, are %s", h)
+	}
+	tests := map[string]Category{"Freiheit": Suffix, "Verlag": Prefix}
+	for _, e := range entries {
+		if c, ok := tests[e.Word]; ok && (len(e.Breaks) != 1 || e.Breaks[0].Category != c) {
+			t.Errorf("%s should have one break of category %d, has %v", e.Word, c, e.Breaks)
+		}
+	}
+	urinstinkt := entries[len(entries)-1]
+	if brk := urinstinkt.Breaks[1]; brk.Category != Syllable|Unwanted || brk.In(AllBreaks) {
+		t.Errorf("Ur=in.stinkt should have an unwanted break, has %v", urinstinkt.Breaks)
+	}
+}
+
+func TestNonStandardBreak(t *testing.T) {
+	zucker := readAll(t, Traditional)[1]
+	want := []Break{{Pos: 3, Category: Syllable, Replacement: &hyphenate.Replacement{Pre: "k", Post: "k", Start: 2, Cut: 2}}}
+	if zucker.Word != "Zucker" || !reflect.DeepEqual(zucker.Breaks, want) {
+		t.Fatalf("Zucker should have breaks %v, has %+v", want, zucker.Breaks)
+	}
+}
+
+type noPatterns struct{}
+
+func (noPatterns) Next() ([]rune, []int, error) { return nil, nil, io.EOF }
+
+func TestLoadExceptions(t *testing.T) {
+	dict, err := hyphenate.LoadPatterns("empty", noPatterns{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadExceptions(dict, strings.NewReader(fixture), Traditional, Compound); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"Abbildung":  "Ab-bildung",
+		"Schiffahrt": "Schiff-fahrt",
+		"Zucker":     "Zucker",
+		"Verlag":     "Verlag",
+	}
+	for word, want := range tests {
+		if h := dict.HyphenationString(word); h != want {
+			t.Errorf("%s should be %s, is %s", word, want, h)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	r := NewReader(strings.NewReader("Zucker;Zu{ck-er\n"), Reformed)
+	if _, err := r.Next(); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("unclosed alternative should be reported for line 1, error is %v", err)
+	}
+}